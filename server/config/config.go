@@ -1,15 +1,51 @@
 package config
 
 import (
+	"errors"
 	"io/ioutil"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
+type Udt struct {
+	Symbol   string `yaml:"symbol"`
+	Decimals int32  `yaml:"decimals"`
+	Args     string `yaml:"args"`
+}
+
+// Network configures one of the networks served, each backed by its own rich node.
+type Network struct {
+	Network      string `yaml:"network"`
+	RichNodeRpc  string `yaml:"rich_node_rpc"`
+	SudtCodeHash string `yaml:"sudt_code_hash"`
+	Udts         []Udt  `yaml:"udts"`
+	// EventsPath is the file block events are recorded in, no events are recorded when empty.
+	EventsPath string `yaml:"events_path"`
+	// DryRun runs submitted transactions on the node before sending them, to report the cycles
+	// they consume.
+	DryRun bool `yaml:"dry_run"`
+}
+
+// Timeouts bounds the time spent serving a request. Endpoints, keyed by path such as /block,
+// override Default; a zero timeout disables the bound.
+type Timeouts struct {
+	Default   time.Duration            `yaml:"default"`
+	Endpoints map[string]time.Duration `yaml:"endpoints"`
+}
+
 type Config struct {
-	Port        uint   `yaml:"port"`
-	Network     string `yaml:"network"`
-	RichNodeRpc string `yaml:"rich_node_rpc"`
+	Port     uint     `yaml:"port"`
+	Timeouts Timeouts `yaml:"timeouts"`
+	// EventsInterval is the interval the node tip is polled at to record block events.
+	EventsInterval time.Duration `yaml:"events_interval"`
+	// FinalityDepth is the number of blocks the current block reported by /network/status, the
+	// latest block served by /block and the default block of /account/balance lag the tip by.
+	FinalityDepth uint64    `yaml:"finality_depth"`
+	Networks      []Network `yaml:"networks"`
+	// Single is the network configured at the top level, as before networks were introduced. It
+	// is served alone when networks is empty.
+	Single Network `yaml:",inline"`
 }
 
 func Init(path string) (*Config, error) {
@@ -23,6 +59,18 @@ func Init(path string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if c.Single.Network != "" {
+		if len(c.Networks) > 0 {
+			return nil, errors.New("network configured both at the top level and in networks")
+		}
+		c.Networks = []Network{c.Single}
+	}
+	if len(c.Networks) == 0 {
+		return nil, errors.New("no network configured")
+	}
+	if c.EventsInterval <= 0 {
+		c.EventsInterval = 5 * time.Second
+	}
 
 	return &c, nil
 }
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		networks []string
+		err      bool
+	}{
+		{
+			name: "networks",
+			config: `
+networks:
+  - network: Mainnet
+    rich_node_rpc: 'http://localhost:8117'
+  - network: Testnet
+    rich_node_rpc: 'http://localhost:8118'
+`,
+			networks: []string{"Mainnet", "Testnet"},
+		},
+		{
+			name: "single network at the top level",
+			config: `
+network: Mainnet
+rich_node_rpc: 'http://localhost:8117'
+sudt_code_hash: '0x5e7a36a77e68eecc013dfa2fe6a23f3b6c344b04005808694ae6dd45eea4cfd5'
+udts: []
+`,
+			networks: []string{"Mainnet"},
+		},
+		{
+			name: "both",
+			config: `
+network: Mainnet
+rich_node_rpc: 'http://localhost:8117'
+networks:
+  - network: Testnet
+    rich_node_rpc: 'http://localhost:8118'
+`,
+			err: true,
+		},
+		{
+			name:   "none",
+			config: "port: 8080\n",
+			err:    true,
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatalf("create directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".yaml")
+			if err := ioutil.WriteFile(path, []byte(test.config), 0644); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			c, err := Init(path)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected an error, got networks %+v", c.Networks)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("init: %v", err)
+			}
+			if len(c.Networks) != len(test.networks) {
+				t.Fatalf("expected networks %v, got %+v", test.networks, c.Networks)
+			}
+			for i, network := range c.Networks {
+				if network.Network != test.networks[i] || network.RichNodeRpc == "" {
+					t.Errorf("expected network %s, got %+v", test.networks[i], network)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -8,41 +9,83 @@ import (
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
+	ethRpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/ququzone/ckb-coinbase-sdk/server/config"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
 	"github.com/ququzone/ckb-coinbase-sdk/server/services"
 	"github.com/ququzone/ckb-rich-sdk-go/rpc"
 )
 
-func NewBlockchainRouter(
+func NewNetworkServices(
 	network *types.NetworkIdentifier,
+	client services.ChainClient,
+	pool services.TxPool,
+	node services.NodeStatus,
+	cells services.CellStatus,
+	udts *services.UdtRegistry,
+	events *services.BlockEvents,
+	depth uint64,
+	dryRun bool,
+) *services.NetworkServices {
+	return &services.NetworkServices{
+		Network:      services.NewNetworkAPIService(network, client, node, depth),
+		Block:        services.NewBlockAPIService(network, client, udts, depth),
+		Account:      services.NewAccountAPIService(network, client, udts, depth),
+		Mempool:      services.NewMempoolAPIService(network, client, pool, udts),
+		Search:       services.NewSearchAPIService(network, client, udts),
+		Events:       services.NewEventsAPIService(network, events),
+		Construction: services.NewConstructionAPIService(network, client, cells, dryRun),
+	}
+}
+
+func NewBlockchainRouter(
+	networks *services.Networks,
 	asserter *asserter.Asserter,
-	client rpc.Client,
 ) http.Handler {
-	networkAPIService := services.NewNetworkAPIService(network, client)
-	networkAPIController := server.NewNetworkAPIController(
-		networkAPIService,
+	networkAPIController := rosetta.NewNetworkAPIController(
+		networks,
+		asserter,
+	)
+
+	blockAPIController := rosetta.NewBlockAPIController(
+		networks,
+		asserter,
+	)
+
+	accountAPIController := rosetta.NewAccountAPIController(
+		networks,
 		asserter,
 	)
 
-	blockAPIService := services.NewBlockAPIService(network, client)
-	blockAPIController := server.NewBlockAPIController(
-		blockAPIService,
+	mempoolAPIController := rosetta.NewMempoolAPIController(
+		networks,
 		asserter,
 	)
 
-	accountAPIService := services.NewAccountAPIService(network, client)
-	accountAPIController := server.NewAccountAPIController(
-		accountAPIService,
+	searchAPIController := rosetta.NewSearchAPIController(
+		networks,
 		asserter,
 	)
 
-	constructionAPIService := services.NewConstructionAPIService(network, client)
-	constructionAPIController := server.NewConstructionAPIController(
-		constructionAPIService,
+	eventsAPIController := rosetta.NewEventsAPIController(
+		networks,
 		asserter,
 	)
 
-	return server.NewRouter(networkAPIController, blockAPIController, accountAPIController, constructionAPIController)
+	constructionAPIController := rosetta.NewConstructionAPIController(
+		networks,
+		asserter,
+	)
+
+	return server.NewRouter(
+		networkAPIController,
+		blockAPIController,
+		accountAPIController,
+		mempoolAPIController,
+		searchAPIController,
+		eventsAPIController,
+		constructionAPIController,
+	)
 }
 
 func main() {
@@ -51,22 +94,51 @@ func main() {
 		log.Fatalf("initial config error: %v", err)
 	}
 
-	client, err := rpc.Dial(c.RichNodeRpc+"/rpc", c.RichNodeRpc+"/indexer")
-	if err != nil {
-		log.Fatalf("dial rich node rpc error: %v", err)
-	}
+	networks := services.NewNetworks(&c.Timeouts)
+	for i := range c.Networks {
+		n := &c.Networks[i]
+
+		client, err := rpc.Dial(n.RichNodeRpc+"/rpc", n.RichNodeRpc+"/indexer")
+		if err != nil {
+			log.Fatalf("dial %s rich node rpc error: %v", n.Network, err)
+		}
+
+		// the helpers calling the node rpc methods the rich node client lacks share a connection
+		nodeRpc, err := ethRpc.Dial(n.RichNodeRpc + "/rpc")
+		if err != nil {
+			log.Fatalf("dial %s node rpc error: %v", n.Network, err)
+		}
+		pool := services.NewTxPool(nodeRpc)
+		node := services.NewNodeStatus(nodeRpc)
+		cells := services.NewCellStatus(nodeRpc)
+
+		udts, err := services.NewUdtRegistry(n)
+		if err != nil {
+			log.Fatalf("initial %s udt registry error: %v", n.Network, err)
+		}
+
+		var events *services.BlockEvents
+		if n.EventsPath != "" {
+			events, err = services.OpenBlockEvents(n.EventsPath)
+			if err != nil {
+				log.Fatalf("open %s block events error: %v", n.Network, err)
+			}
+			go services.NewBlockWatcher(client, events).Run(context.Background(), c.EventsInterval)
+		}
 
-	network := &types.NetworkIdentifier{
-		Blockchain: "CKB",
-		Network:    c.Network,
+		network := &types.NetworkIdentifier{
+			Blockchain: "CKB",
+			Network:    n.Network,
+		}
+		networks.Add(network, NewNetworkServices(network, client, pool, node, cells, udts, events, c.FinalityDepth, n.DryRun))
 	}
 
-	asserter, err := asserter.NewServer([]*types.NetworkIdentifier{network})
+	asserter, err := asserter.NewServer(networks.Identifiers())
 	if err != nil {
 		log.Fatalf("initial server error: %v", err)
 	}
 
-	router := NewBlockchainRouter(network, asserter, client)
+	router := NewBlockchainRouter(networks, asserter)
 	log.Printf("Listening on port %d\n", c.Port)
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", c.Port), router))
 }
@@ -0,0 +1,117 @@
+// Package rosetta provides the Rosetta endpoints introduced after Rosetta 1.3.0, which is the version
+// implemented by rosetta-sdk-go, following the servicer and controller layout of the SDK server.
+// Every endpoint is served by the controllers of this package, which report failures as Error.
+package rosetta
+
+import (
+	"context"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// NetworkAPIServicer defines the api actions for the NetworkAPI service, of which NetworkStatus
+// returns the status fields missing from types.NetworkStatusResponse.
+type NetworkAPIServicer interface {
+	NetworkList(
+		context.Context,
+		*types.MetadataRequest,
+	) (*types.NetworkListResponse, *Error)
+	NetworkStatus(
+		context.Context,
+		*types.NetworkRequest,
+	) (*NetworkStatusResponse, *Error)
+	NetworkOptions(
+		context.Context,
+		*types.NetworkRequest,
+	) (*NetworkOptionsResponse, *Error)
+}
+
+// BlockAPIServicer defines the api actions for the BlockAPI service.
+type BlockAPIServicer interface {
+	Block(
+		context.Context,
+		*types.BlockRequest,
+	) (*types.BlockResponse, *Error)
+	BlockTransaction(
+		context.Context,
+		*types.BlockTransactionRequest,
+	) (*types.BlockTransactionResponse, *Error)
+}
+
+// AccountAPIServicer defines the api actions for the AccountAPI service, including the endpoints
+// missing from server.AccountAPIServicer.
+type AccountAPIServicer interface {
+	AccountBalance(
+		context.Context,
+		*types.AccountBalanceRequest,
+	) (*types.AccountBalanceResponse, *Error)
+	AccountCoins(
+		context.Context,
+		*AccountCoinsRequest,
+	) (*AccountCoinsResponse, *Error)
+}
+
+// MempoolAPIServicer defines the api actions for the MempoolAPI service.
+type MempoolAPIServicer interface {
+	Mempool(
+		context.Context,
+		*types.MempoolRequest,
+	) (*types.MempoolResponse, *Error)
+	MempoolTransaction(
+		context.Context,
+		*types.MempoolTransactionRequest,
+	) (*types.MempoolTransactionResponse, *Error)
+}
+
+// ConstructionAPIServicer defines the api actions for the ConstructionAPI service, including the
+// endpoints of the construction flow missing from server.ConstructionAPIServicer.
+type ConstructionAPIServicer interface {
+	ConstructionMetadata(
+		context.Context,
+		*types.ConstructionMetadataRequest,
+	) (*types.ConstructionMetadataResponse, *Error)
+	ConstructionSubmit(
+		context.Context,
+		*types.ConstructionSubmitRequest,
+	) (*types.ConstructionSubmitResponse, *Error)
+	ConstructionDerive(
+		context.Context,
+		*ConstructionDeriveRequest,
+	) (*ConstructionDeriveResponse, *Error)
+	ConstructionPreprocess(
+		context.Context,
+		*ConstructionPreprocessRequest,
+	) (*ConstructionPreprocessResponse, *Error)
+	ConstructionPayloads(
+		context.Context,
+		*ConstructionPayloadsRequest,
+	) (*ConstructionPayloadsResponse, *Error)
+	ConstructionParse(
+		context.Context,
+		*ConstructionParseRequest,
+	) (*ConstructionParseResponse, *Error)
+	ConstructionCombine(
+		context.Context,
+		*ConstructionCombineRequest,
+	) (*ConstructionCombineResponse, *Error)
+	ConstructionHash(
+		context.Context,
+		*ConstructionHashRequest,
+	) (*TransactionIdentifierResponse, *Error)
+}
+
+// SearchAPIServicer defines the api actions for the SearchAPI service.
+type SearchAPIServicer interface {
+	SearchTransactions(
+		context.Context,
+		*SearchTransactionsRequest,
+	) (*SearchTransactionsResponse, *Error)
+}
+
+// EventsAPIServicer defines the api actions for the EventsAPI service.
+type EventsAPIServicer interface {
+	EventsBlocks(
+		context.Context,
+		*EventsBlocksRequest,
+	) (*EventsBlocksResponse, *Error)
+}
@@ -0,0 +1,84 @@
+package rosetta
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// A AccountAPIController binds http requests to an api service and writes the service results to
+// the http response.
+type AccountAPIController struct {
+	service  AccountAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewAccountAPIController creates a default api controller
+func NewAccountAPIController(
+	s AccountAPIServicer,
+	asserter *asserter.Asserter,
+) server.Router {
+	return &AccountAPIController{
+		service:  s,
+		asserter: asserter,
+	}
+}
+
+// Routes returns all of the api route for the AccountAPIController
+func (c *AccountAPIController) Routes() server.Routes {
+	return server.Routes{
+		{
+			Name:        "AccountBalance",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/account/balance",
+			HandlerFunc: c.AccountBalance,
+		},
+		{
+			Name:        "AccountCoins",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/account/coins",
+			HandlerFunc: c.AccountCoins,
+		},
+	}
+}
+
+// AccountBalance - Get an Account Balance
+func (c *AccountAPIController) AccountBalance(w http.ResponseWriter, r *http.Request) {
+	request := &types.AccountBalanceRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := c.asserter.AccountBalanceRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.AccountBalance(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// AccountCoins - Get an Account's Unspent Coins
+func (c *AccountAPIController) AccountCoins(w http.ResponseWriter, r *http.Request) {
+	request := &AccountCoinsRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := asserter.AccountIdentifier(request.AccountIdentifier); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.AccountCoins(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
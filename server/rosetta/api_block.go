@@ -0,0 +1,79 @@
+package rosetta
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// A BlockAPIController binds http requests to an api service and writes the service results to the
+// http response. Unlike server.BlockAPIController it accepts a /block request without a block
+// identifier, which asks for the latest block, and leaves validating the identifier to the service.
+type BlockAPIController struct {
+	service  BlockAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewBlockAPIController creates a default api controller
+func NewBlockAPIController(
+	s BlockAPIServicer,
+	asserter *asserter.Asserter,
+) server.Router {
+	return &BlockAPIController{
+		service:  s,
+		asserter: asserter,
+	}
+}
+
+// Routes returns all of the api route for the BlockAPIController
+func (c *BlockAPIController) Routes() server.Routes {
+	return server.Routes{
+		{
+			Name:        "Block",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/block",
+			HandlerFunc: c.Block,
+		},
+		{
+			Name:        "BlockTransaction",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/block/transaction",
+			HandlerFunc: c.BlockTransaction,
+		},
+	}
+}
+
+// Block - Get a Block
+func (c *BlockAPIController) Block(w http.ResponseWriter, r *http.Request) {
+	request := &types.BlockRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	result, serviceErr := c.service.Block(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// BlockTransaction - Get a Block Transaction
+func (c *BlockAPIController) BlockTransaction(w http.ResponseWriter, r *http.Request) {
+	request := &types.BlockTransactionRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := c.asserter.BlockTransactionRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.BlockTransaction(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
@@ -0,0 +1,234 @@
+package rosetta
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// A ConstructionAPIController binds http requests to an api service and writes the service results
+// to the http response.
+type ConstructionAPIController struct {
+	service  ConstructionAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewConstructionAPIController creates a default api controller
+func NewConstructionAPIController(
+	s ConstructionAPIServicer,
+	asserter *asserter.Asserter,
+) server.Router {
+	return &ConstructionAPIController{
+		service:  s,
+		asserter: asserter,
+	}
+}
+
+// Routes returns all of the api route for the ConstructionAPIController
+func (c *ConstructionAPIController) Routes() server.Routes {
+	return server.Routes{
+		{
+			Name:        "ConstructionMetadata",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/construction/metadata",
+			HandlerFunc: c.ConstructionMetadata,
+		},
+		{
+			Name:        "ConstructionSubmit",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/construction/submit",
+			HandlerFunc: c.ConstructionSubmit,
+		},
+		{
+			Name:        "ConstructionDerive",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/construction/derive",
+			HandlerFunc: c.ConstructionDerive,
+		},
+		{
+			Name:        "ConstructionPreprocess",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/construction/preprocess",
+			HandlerFunc: c.ConstructionPreprocess,
+		},
+		{
+			Name:        "ConstructionPayloads",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/construction/payloads",
+			HandlerFunc: c.ConstructionPayloads,
+		},
+		{
+			Name:        "ConstructionParse",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/construction/parse",
+			HandlerFunc: c.ConstructionParse,
+		},
+		{
+			Name:        "ConstructionCombine",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/construction/combine",
+			HandlerFunc: c.ConstructionCombine,
+		},
+		{
+			Name:        "ConstructionHash",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/construction/hash",
+			HandlerFunc: c.ConstructionHash,
+		},
+	}
+}
+
+// ConstructionMetadata - Get Transaction Construction Metadata
+func (c *ConstructionAPIController) ConstructionMetadata(w http.ResponseWriter, r *http.Request) {
+	request := &types.ConstructionMetadataRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := c.asserter.ConstructionMetadataRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.ConstructionMetadata(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// ConstructionSubmit - Submit a Signed Transaction
+func (c *ConstructionAPIController) ConstructionSubmit(w http.ResponseWriter, r *http.Request) {
+	request := &types.ConstructionSubmitRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := c.asserter.ConstructionSubmitRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.ConstructionSubmit(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// ConstructionDerive - Derive an Address from a PublicKey
+func (c *ConstructionAPIController) ConstructionDerive(w http.ResponseWriter, r *http.Request) {
+	request := &ConstructionDeriveRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := assertConstructionDeriveRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.ConstructionDerive(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// ConstructionPreprocess - Create a Request to Fetch Metadata
+func (c *ConstructionAPIController) ConstructionPreprocess(w http.ResponseWriter, r *http.Request) {
+	request := &ConstructionPreprocessRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := assertConstructionPreprocessRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.ConstructionPreprocess(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// ConstructionPayloads - Generate an Unsigned Transaction and Signing Payloads
+func (c *ConstructionAPIController) ConstructionPayloads(w http.ResponseWriter, r *http.Request) {
+	request := &ConstructionPayloadsRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := assertConstructionPayloadsRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.ConstructionPayloads(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// ConstructionParse - Parse a Transaction
+func (c *ConstructionAPIController) ConstructionParse(w http.ResponseWriter, r *http.Request) {
+	request := &ConstructionParseRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := assertConstructionParseRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.ConstructionParse(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// ConstructionCombine - Create Network Transaction from Signatures
+func (c *ConstructionAPIController) ConstructionCombine(w http.ResponseWriter, r *http.Request) {
+	request := &ConstructionCombineRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := assertConstructionCombineRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.ConstructionCombine(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// ConstructionHash - Get the Hash of a Signed Transaction
+func (c *ConstructionAPIController) ConstructionHash(w http.ResponseWriter, r *http.Request) {
+	request := &ConstructionHashRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := assertConstructionHashRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.ConstructionHash(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
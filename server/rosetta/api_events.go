@@ -0,0 +1,53 @@
+package rosetta
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// An EventsAPIController binds http requests to an api service and writes the service results to
+// the http response.
+type EventsAPIController struct {
+	service  EventsAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewEventsAPIController creates a default api controller
+func NewEventsAPIController(
+	s EventsAPIServicer,
+	asserter *asserter.Asserter,
+) server.Router {
+	return &EventsAPIController{
+		service:  s,
+		asserter: asserter,
+	}
+}
+
+// Routes returns all of the api route for the EventsAPIController
+func (c *EventsAPIController) Routes() server.Routes {
+	return server.Routes{
+		{
+			Name:        "EventsBlocks",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/events/blocks",
+			HandlerFunc: c.EventsBlocks,
+		},
+	}
+}
+
+// EventsBlocks - Get a range of BlockEvents
+func (c *EventsAPIController) EventsBlocks(w http.ResponseWriter, r *http.Request) {
+	request := &EventsBlocksRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	result, serviceErr := c.service.EventsBlocks(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
@@ -0,0 +1,78 @@
+package rosetta
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// A MempoolAPIController binds http requests to an api service and writes the service results to
+// the http response.
+type MempoolAPIController struct {
+	service  MempoolAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewMempoolAPIController creates a default api controller
+func NewMempoolAPIController(
+	s MempoolAPIServicer,
+	asserter *asserter.Asserter,
+) server.Router {
+	return &MempoolAPIController{
+		service:  s,
+		asserter: asserter,
+	}
+}
+
+// Routes returns all of the api route for the MempoolAPIController
+func (c *MempoolAPIController) Routes() server.Routes {
+	return server.Routes{
+		{
+			Name:        "Mempool",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/mempool",
+			HandlerFunc: c.Mempool,
+		},
+		{
+			Name:        "MempoolTransaction",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/mempool/transaction",
+			HandlerFunc: c.MempoolTransaction,
+		},
+	}
+}
+
+// Mempool - Get All Mempool Transactions
+func (c *MempoolAPIController) Mempool(w http.ResponseWriter, r *http.Request) {
+	request := &types.MempoolRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	result, serviceErr := c.service.Mempool(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// MempoolTransaction - Get a Mempool Transaction
+func (c *MempoolAPIController) MempoolTransaction(w http.ResponseWriter, r *http.Request) {
+	request := &types.MempoolTransactionRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	if err := c.asserter.MempoolTransactionRequest(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.MempoolTransaction(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
@@ -0,0 +1,93 @@
+package rosetta
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// A NetworkAPIController binds http requests to an api service and writes the service results to
+// the http response. It replaces server.NetworkAPIController to serve the extended
+// NetworkStatusResponse.
+type NetworkAPIController struct {
+	service  NetworkAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewNetworkAPIController creates a default api controller
+func NewNetworkAPIController(
+	s NetworkAPIServicer,
+	asserter *asserter.Asserter,
+) server.Router {
+	return &NetworkAPIController{
+		service:  s,
+		asserter: asserter,
+	}
+}
+
+// Routes returns all of the api route for the NetworkAPIController
+func (c *NetworkAPIController) Routes() server.Routes {
+	return server.Routes{
+		{
+			Name:        "NetworkList",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/network/list",
+			HandlerFunc: c.NetworkList,
+		},
+		{
+			Name:        "NetworkOptions",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/network/options",
+			HandlerFunc: c.NetworkOptions,
+		},
+		{
+			Name:        "NetworkStatus",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/network/status",
+			HandlerFunc: c.NetworkStatus,
+		},
+	}
+}
+
+// NetworkList - Get List of Available Networks
+func (c *NetworkAPIController) NetworkList(w http.ResponseWriter, r *http.Request) {
+	request := &types.MetadataRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		encodeRequestError(w, err)
+
+		return
+	}
+
+	result, serviceErr := c.service.NetworkList(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// NetworkOptions - Get Network Options
+func (c *NetworkAPIController) NetworkOptions(w http.ResponseWriter, r *http.Request) {
+	request := &types.NetworkRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	result, serviceErr := c.service.NetworkOptions(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
+
+// NetworkStatus - Get Network Status
+func (c *NetworkAPIController) NetworkStatus(w http.ResponseWriter, r *http.Request) {
+	request := &types.NetworkRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	result, serviceErr := c.service.NetworkStatus(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
@@ -0,0 +1,53 @@
+package rosetta
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// A SearchAPIController binds http requests to an api service and writes the service results to
+// the http response.
+type SearchAPIController struct {
+	service  SearchAPIServicer
+	asserter *asserter.Asserter
+}
+
+// NewSearchAPIController creates a default api controller
+func NewSearchAPIController(
+	s SearchAPIServicer,
+	asserter *asserter.Asserter,
+) server.Router {
+	return &SearchAPIController{
+		service:  s,
+		asserter: asserter,
+	}
+}
+
+// Routes returns all of the api route for the SearchAPIController
+func (c *SearchAPIController) Routes() server.Routes {
+	return server.Routes{
+		{
+			Name:        "SearchTransactions",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/search/transactions",
+			HandlerFunc: c.SearchTransactions,
+		},
+	}
+}
+
+// SearchTransactions - Search for Transactions
+func (c *SearchAPIController) SearchTransactions(w http.ResponseWriter, r *http.Request) {
+	request := &SearchTransactionsRequest{}
+	if !decodeRequest(w, r, c.asserter, request, func() *types.NetworkIdentifier {
+		return request.NetworkIdentifier
+	}) {
+		return
+	}
+
+	result, serviceErr := c.service.SearchTransactions(r.Context(), request)
+	encodeResponse(w, result, serviceErr)
+}
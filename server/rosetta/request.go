@@ -0,0 +1,104 @@
+package rosetta
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// The assertions of the construction requests the asserter of the rosetta-sdk does not know. The
+// network of a request is asserted when it is decoded.
+
+func assertConstructionDeriveRequest(request *ConstructionDeriveRequest) error {
+	if request.PublicKey == nil {
+		return errors.New("ConstructionDeriveRequest.PublicKey is nil")
+	}
+
+	return assertPublicKey(request.PublicKey)
+}
+
+func assertConstructionPreprocessRequest(request *ConstructionPreprocessRequest) error {
+	return assertIntent(request.Operations)
+}
+
+func assertConstructionPayloadsRequest(request *ConstructionPayloadsRequest) error {
+	return assertIntent(request.Operations)
+}
+
+func assertConstructionParseRequest(request *ConstructionParseRequest) error {
+	if request.Transaction == "" {
+		return errors.New("ConstructionParseRequest.Transaction is empty")
+	}
+
+	return nil
+}
+
+func assertConstructionCombineRequest(request *ConstructionCombineRequest) error {
+	if request.UnsignedTransaction == "" {
+		return errors.New("ConstructionCombineRequest.UnsignedTransaction is empty")
+	}
+	if len(request.Signatures) == 0 {
+		return errors.New("ConstructionCombineRequest.Signatures is empty")
+	}
+	for i, signature := range request.Signatures {
+		if signature == nil {
+			return fmt.Errorf("Signature %d is nil", i)
+		}
+		if signature.SigningPayload == nil || signature.SigningPayload.HexBytes == "" {
+			return fmt.Errorf("Signature %d has no SigningPayload", i)
+		}
+		if signature.HexBytes == "" {
+			return fmt.Errorf("Signature %d is empty", i)
+		}
+	}
+
+	return nil
+}
+
+func assertConstructionHashRequest(request *ConstructionHashRequest) error {
+	if request.SignedTransaction == "" {
+		return errors.New("ConstructionHashRequest.SignedTransaction is empty")
+	}
+
+	return nil
+}
+
+func assertPublicKey(publicKey *PublicKey) error {
+	if publicKey.HexBytes == "" {
+		return errors.New("PublicKey.HexBytes is empty")
+	}
+	if publicKey.CurveType == "" {
+		return errors.New("PublicKey.CurveType is empty")
+	}
+
+	return nil
+}
+
+// assertIntent asserts the operations of a transaction to construct, which have no status unlike
+// the operations of a block.
+func assertIntent(operations []*types.Operation) error {
+	if len(operations) == 0 {
+		return errors.New("Operations is empty")
+	}
+	for i, operation := range operations {
+		if operation == nil {
+			return errors.New("Operation is nil")
+		}
+		if err := asserter.OperationIdentifier(operation.OperationIdentifier, int64(i)); err != nil {
+			return err
+		}
+		if operation.Type == "" {
+			return errors.New("Operation.Type is empty")
+		}
+		if err := asserter.AccountIdentifier(operation.Account); err != nil {
+			return err
+		}
+		if err := asserter.Amount(operation.Amount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package rosetta
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// InvalidRequestError is returned for a request which cannot be decoded or is not well-formatted,
+// before it reaches a service.
+var InvalidRequestError = &Error{
+	Code:        17,
+	Message:     "invalid request",
+	Description: "The request body cannot be decoded or fails the Rosetta request validation, the details hold the reason.",
+	Retriable:   false,
+}
+
+// decodeRequest decodes the json body of r into request and asserts that the network it is sent to
+// is supported, writing the error response if it is not.
+func decodeRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	asserter *asserter.Asserter,
+	request interface{},
+	network func() *types.NetworkIdentifier,
+) bool {
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		encodeRequestError(w, err)
+
+		return false
+	}
+
+	if err := assertNetwork(asserter, network()); err != nil {
+		encodeRequestError(w, err)
+
+		return false
+	}
+
+	return true
+}
+
+func assertNetwork(a *asserter.Asserter, network *types.NetworkIdentifier) error {
+	if network == nil {
+		return errors.New("NetworkIdentifier is nil")
+	}
+	if network.Blockchain == "" || network.Network == "" {
+		return errors.New("NetworkIdentifier is invalid")
+	}
+
+	return a.SupportedNetwork(network)
+}
+
+// encodeRequestError writes InvalidRequestError caused by err.
+func encodeRequestError(w http.ResponseWriter, err error) {
+	requestErr := *InvalidRequestError
+	requestErr.Details = map[string]interface{}{
+		"error": err.Error(),
+	}
+	server.EncodeJSONResponse(&requestErr, http.StatusInternalServerError, w)
+}
+
+// encodeResponse writes serviceErr if it is set, result otherwise.
+func encodeResponse(w http.ResponseWriter, result interface{}, serviceErr *Error) {
+	if serviceErr != nil {
+		server.EncodeJSONResponse(serviceErr, http.StatusInternalServerError, w)
+
+		return
+	}
+
+	server.EncodeJSONResponse(result, http.StatusOK, w)
+}
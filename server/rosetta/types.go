@@ -0,0 +1,258 @@
+package rosetta
+
+import (
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// CurveTypeSecp256k1 is the SEC compressed secp256k1 curve.
+	CurveTypeSecp256k1 = "secp256k1"
+
+	// SignatureTypeEcdsaRecovery is a 65 byte r || s || v signature.
+	SignatureTypeEcdsaRecovery = "ecdsa_recovery"
+
+	// CoinCreated is the action of an operation creating a coin.
+	CoinCreated = "coin_created"
+
+	// CoinSpent is the action of an operation spending a coin.
+	CoinSpent = "coin_spent"
+
+	// BlockAdded is the type of the event of a block becoming part of the canonical chain.
+	BlockAdded = "block_added"
+
+	// BlockRemoved is the type of the event of a block being orphaned by a reorganization.
+	BlockRemoved = "block_removed"
+)
+
+// Error is returned by every endpoint which fails. It adds the description of the error and the
+// details of its cause, introduced after Rosetta 1.3.0, to types.Error.
+type Error struct {
+	Code        int32                  `json:"code"`
+	Message     string                 `json:"message"`
+	Description string                 `json:"description,omitempty"`
+	Retriable   bool                   `json:"retriable"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+}
+
+// Allow specifies the statuses, operation types and errors supported by the implementation, the
+// errors being listed with their descriptions.
+type Allow struct {
+	OperationStatuses []*types.OperationStatus `json:"operation_statuses"`
+	OperationTypes    []string                 `json:"operation_types"`
+	Errors            []*Error                 `json:"errors"`
+}
+
+// NetworkOptionsResponse contains the version information and the allowed network-specific types
+// of the implementation.
+type NetworkOptionsResponse struct {
+	Version *types.Version `json:"version"`
+	Allow   *Allow         `json:"allow"`
+}
+
+// SyncStatus is the progress of the node syncing the chain, which is missing from
+// types.NetworkStatusResponse in Rosetta 1.3.0. TargetIndex is the best block known to the node.
+type SyncStatus struct {
+	CurrentIndex int64   `json:"current_index"`
+	TargetIndex  *int64  `json:"target_index,omitempty"`
+	Stage        *string `json:"stage,omitempty"`
+	Synced       *bool   `json:"synced,omitempty"`
+}
+
+// IndexerStatus reports the tip of the indexer serving balances and searches and the number of
+// blocks it lags the tip of the node by.
+type IndexerStatus struct {
+	TipBlockIdentifier *types.BlockIdentifier `json:"tip_block_identifier"`
+	Lag                int64                  `json:"lag"`
+}
+
+// NetworkStatusResponse contains the types.NetworkStatusResponse fields along with the sync status
+// of the node and the status of its indexer.
+type NetworkStatusResponse struct {
+	CurrentBlockIdentifier *types.BlockIdentifier `json:"current_block_identifier"`
+	CurrentBlockTimestamp  int64                  `json:"current_block_timestamp"`
+	GenesisBlockIdentifier *types.BlockIdentifier `json:"genesis_block_identifier"`
+	SyncStatus             *SyncStatus            `json:"sync_status,omitempty"`
+	IndexerStatus          *IndexerStatus         `json:"indexer_status,omitempty"`
+	Peers                  []*types.Peer          `json:"peers"`
+}
+
+// PublicKey contains a public key byte array for a particular curve encoded in hex.
+type PublicKey struct {
+	HexBytes  string `json:"hex_bytes"`
+	CurveType string `json:"curve_type"`
+}
+
+// SigningPayload is signed by the client with the keypair associated with an address.
+type SigningPayload struct {
+	Address       string `json:"address"`
+	HexBytes      string `json:"hex_bytes"`
+	SignatureType string `json:"signature_type,omitempty"`
+}
+
+// Signature contains the payload that was signed, the public key of the keypair used to produce
+// the signature and the signature encoded in hex.
+type Signature struct {
+	SigningPayload *SigningPayload `json:"signing_payload"`
+	PublicKey      *PublicKey      `json:"public_key"`
+	SignatureType  string          `json:"signature_type"`
+	HexBytes       string          `json:"hex_bytes"`
+}
+
+// CoinIdentifier uniquely identifies a coin, which is a CKB cell referenced as tx_hash:index.
+type CoinIdentifier struct {
+	Identifier string `json:"identifier"`
+}
+
+// CoinChange is used to represent a change in state of some coin identified by a coin identifier.
+type CoinChange struct {
+	CoinIdentifier *CoinIdentifier `json:"coin_identifier"`
+	CoinAction     string          `json:"coin_action"`
+}
+
+// Coin is a CKB cell and the capacity it holds. Metadata describes the cell beyond its capacity,
+// which Rosetta has no field for.
+type Coin struct {
+	CoinIdentifier *CoinIdentifier        `json:"coin_identifier"`
+	Amount         *types.Amount          `json:"amount"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// AccountCoinsRequest is passed to the /account/coins endpoint.
+type AccountCoinsRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	AccountIdentifier *types.AccountIdentifier `json:"account_identifier"`
+	IncludeMempool    bool                     `json:"include_mempool"`
+	Currencies        []*types.Currency        `json:"currencies,omitempty"`
+}
+
+// AccountCoinsResponse contains the live cells of an account at a block.
+type AccountCoinsResponse struct {
+	BlockIdentifier *types.BlockIdentifier `json:"block_identifier"`
+	Coins           []*Coin                `json:"coins"`
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// SearchTransactionsRequest is passed to the /search/transactions endpoint. Transactions are
+// searched by the lock script of the address, newest first, between MinBlock and MaxBlock
+// inclusive. Cursor is the NextCursor of the previous page.
+type SearchTransactionsRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	AccountIdentifier *types.AccountIdentifier `json:"account_identifier,omitempty"`
+	Address           string                   `json:"address,omitempty"`
+	MinBlock          *int64                   `json:"min_block,omitempty"`
+	MaxBlock          *int64                   `json:"max_block,omitempty"`
+	Limit             *int64                   `json:"limit,omitempty"`
+	Cursor            string                   `json:"cursor,omitempty"`
+}
+
+// BlockTransaction contains a transaction and the block it is committed in.
+type BlockTransaction struct {
+	BlockIdentifier *types.BlockIdentifier `json:"block_identifier"`
+	Transaction     *types.Transaction     `json:"transaction"`
+}
+
+// SearchTransactionsResponse contains a page of the transactions matching a search. NextCursor is
+// empty on the last page.
+type SearchTransactionsResponse struct {
+	Transactions []*BlockTransaction `json:"transactions"`
+	NextCursor   string              `json:"next_cursor,omitempty"`
+}
+
+// BlockEvent records a block being added to or removed from the canonical chain. Sequence numbers
+// start at 0 and increase by 1 with each event.
+type BlockEvent struct {
+	Sequence        int64                  `json:"sequence"`
+	BlockIdentifier *types.BlockIdentifier `json:"block_identifier"`
+	Type            string                 `json:"type"`
+}
+
+// EventsBlocksRequest is passed to the /events/blocks endpoint. Offset is the sequence number of
+// the first event returned.
+type EventsBlocksRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	Offset            *int64                   `json:"offset,omitempty"`
+	Limit             *int64                   `json:"limit,omitempty"`
+}
+
+// EventsBlocksResponse contains the events requested and the sequence number of the latest event,
+// which is -1 before the first event.
+type EventsBlocksResponse struct {
+	MaxSequence int64         `json:"max_sequence"`
+	Events      []*BlockEvent `json:"events"`
+}
+
+// ConstructionDeriveRequest is passed to the /construction/derive endpoint.
+type ConstructionDeriveRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	PublicKey         *PublicKey               `json:"public_key"`
+	Metadata          map[string]interface{}   `json:"metadata,omitempty"`
+}
+
+// ConstructionDeriveResponse is returned by the /construction/derive endpoint.
+type ConstructionDeriveResponse struct {
+	Address  string                 `json:"address"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ConstructionPreprocessRequest is passed to the /construction/preprocess endpoint.
+type ConstructionPreprocessRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	Operations        []*types.Operation       `json:"operations"`
+	Metadata          map[string]interface{}   `json:"metadata,omitempty"`
+}
+
+// ConstructionPreprocessResponse contains the options passed to /construction/metadata.
+type ConstructionPreprocessResponse struct {
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// ConstructionPayloadsRequest is the request to /construction/payloads.
+type ConstructionPayloadsRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	Operations        []*types.Operation       `json:"operations"`
+	Metadata          map[string]interface{}   `json:"metadata,omitempty"`
+}
+
+// ConstructionPayloadsResponse is returned by /construction/payloads.
+type ConstructionPayloadsResponse struct {
+	UnsignedTransaction string            `json:"unsigned_transaction"`
+	Payloads            []*SigningPayload `json:"payloads"`
+}
+
+// ConstructionParseRequest is the input to the /construction/parse endpoint.
+type ConstructionParseRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	Signed            bool                     `json:"signed"`
+	Transaction       string                   `json:"transaction"`
+}
+
+// ConstructionParseResponse contains the operations of a transaction and its signers.
+type ConstructionParseResponse struct {
+	Operations []*types.Operation     `json:"operations"`
+	Signers    []string               `json:"signers"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ConstructionCombineRequest is the input to the /construction/combine endpoint.
+type ConstructionCombineRequest struct {
+	NetworkIdentifier   *types.NetworkIdentifier `json:"network_identifier"`
+	UnsignedTransaction string                   `json:"unsigned_transaction"`
+	Signatures          []*Signature             `json:"signatures"`
+}
+
+// ConstructionCombineResponse is returned by /construction/combine.
+type ConstructionCombineResponse struct {
+	SignedTransaction string `json:"signed_transaction"`
+}
+
+// ConstructionHashRequest is the input to the /construction/hash endpoint.
+type ConstructionHashRequest struct {
+	NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+	SignedTransaction string                   `json:"signed_transaction"`
+}
+
+// TransactionIdentifierResponse contains the transaction identifier of a signed transaction.
+type TransactionIdentifierResponse struct {
+	TransactionIdentifier *types.TransactionIdentifier `json:"transaction_identifier"`
+	Metadata              map[string]interface{}       `json:"metadata,omitempty"`
+}
@@ -3,25 +3,37 @@ package services
 import (
 	"context"
 	"fmt"
+	"math/big"
 
-	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
 	"github.com/ququzone/ckb-rich-sdk-go/indexer"
-	"github.com/ququzone/ckb-rich-sdk-go/rpc"
 	"github.com/ququzone/ckb-sdk-go/address"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
 )
 
-// AccountAPIService implements the server.AccountAPIServicer interface.
+const (
+	pageSize = 1000
+	// coinsAttempts is how many times the cells are listed while the tip keeps moving.
+	coinsAttempts = 3
+)
+
+// AccountAPIService implements the rosetta.AccountAPIServicer interface.
 type AccountAPIService struct {
 	network *types.NetworkIdentifier
-	client  rpc.Client
+	client  ChainClient
+	udts    *UdtRegistry
+	depth   uint64
 }
 
-// NewAccountAPIService creates a new instance of a AccountAPIService.
-func NewAccountAPIService(network *types.NetworkIdentifier, client rpc.Client) server.AccountAPIServicer {
+// NewAccountAPIService creates a new instance of a AccountAPIService. A balance requested without a
+// block identifier is the balance at the block depth blocks below the indexer tip.
+func NewAccountAPIService(network *types.NetworkIdentifier, client ChainClient, udts *UdtRegistry, depth uint64) rosetta.AccountAPIServicer {
 	return &AccountAPIService{
 		network: network,
 		client:  client,
+		udts:    udts,
+		depth:   depth,
 	}
 }
 
@@ -29,30 +41,340 @@ func NewAccountAPIService(network *types.NetworkIdentifier, client rpc.Client) s
 func (s *AccountAPIService) AccountBalance(
 	ctx context.Context,
 	request *types.AccountBalanceRequest,
-) (*types.AccountBalanceResponse, *types.Error) {
+) (*types.AccountBalanceResponse, *rosetta.Error) {
+	if isPseudoAccount(request.AccountIdentifier.Address) {
+		return nil, PseudoAccountError
+	}
 	addr, err := address.Parse(request.AccountIdentifier.Address)
 	if err != nil {
-		return nil, AddressError
+		return nil, wrapError(AddressError, err)
 	}
 
-	capacity, err := s.client.GetCellsCapacity(context.Background(), &indexer.SearchKey{
+	capacity, err := s.client.GetCellsCapacity(ctx, &indexer.SearchKey{
 		Script:     addr.Script,
 		ScriptType: indexer.ScriptTypeLock,
 	})
 	if err != nil {
-		return nil, RpcError
+		return nil, wrapError(RpcError, err)
+	}
+
+	balances := newBalanceSheet()
+	balances.add(CkbCurrency, new(big.Int).SetUint64(capacity.Capacity))
+	err = s.udtBalances(ctx, addr.Script, balances)
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+
+	blockIdentifier := &types.BlockIdentifier{
+		Index: int64(capacity.BlockNumber),
+		Hash:  capacity.BlockHash.String(),
+	}
+	identifier := request.BlockIdentifier
+	if identifier == nil && s.depth > 0 {
+		index := int64(finalBlock(capacity.BlockNumber, s.depth))
+		identifier = &types.PartialBlockIdentifier{Index: &index}
+	}
+	if identifier != nil {
+		header, err := s.header(ctx, identifier)
+		if err == errInvalidBlockIdentifier {
+			return nil, BlockIdentifierError
+		}
+		if err == errBlockNotFound {
+			return nil, BlockNotFoundError
+		}
+		if err == errBlockMismatch {
+			return nil, wrapError(BlockNotFoundError, err)
+		}
+		if err != nil {
+			return nil, wrapError(RpcError, err)
+		}
+		if header.Number > capacity.BlockNumber {
+			return nil, wrapError(RpcError, fmt.Errorf("%w: indexed up to block %d, requested %d", errIndexerBehind, capacity.BlockNumber, header.Number))
+		}
+		err = s.rollbackBalances(ctx, addr.Script, header.Number, capacity.BlockNumber, balances)
+		if err != nil {
+			return nil, wrapError(RpcError, err)
+		}
+		blockIdentifier = &types.BlockIdentifier{
+			Index: int64(header.Number),
+			Hash:  header.Hash.String(),
+		}
 	}
 
 	return &types.AccountBalanceResponse{
-		BlockIdentifier: &types.BlockIdentifier{
-			Index: int64(capacity.BlockNumber),
-			Hash:  capacity.BlockHash.String(),
-		},
-		Balances: []*types.Amount{
-			{
-				Value:    fmt.Sprintf("%d", capacity.Capacity),
-				Currency: CkbCurrency,
-			},
-		},
+		BlockIdentifier: blockIdentifier,
+		Balances:        balances.amounts(),
 	}, nil
 }
+
+// AccountCoins implements the /account/coins endpoint. The coins are the live cells locked by the
+// address, of which the metadata reports whether they have a type script and the length of their data.
+func (s *AccountAPIService) AccountCoins(
+	ctx context.Context,
+	request *rosetta.AccountCoinsRequest,
+) (*rosetta.AccountCoinsResponse, *rosetta.Error) {
+	if isPseudoAccount(request.AccountIdentifier.Address) {
+		return nil, PseudoAccountError
+	}
+	addr, err := address.Parse(request.AccountIdentifier.Address)
+	if err != nil {
+		return nil, wrapError(AddressError, err)
+	}
+
+	// the cells are a snapshot at the tip only if it did not move while they were paged through
+	tip, err := s.client.GetTip(ctx)
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+	for attempt := 1; ; attempt++ {
+		coins, err := s.coins(ctx, addr.Script)
+		if err != nil {
+			return nil, wrapError(RpcError, err)
+		}
+		after, err := s.client.GetTip(ctx)
+		if err != nil {
+			return nil, wrapError(RpcError, err)
+		}
+
+		if after.BlockHash == tip.BlockHash {
+			return &rosetta.AccountCoinsResponse{
+				BlockIdentifier: &types.BlockIdentifier{
+					Index: int64(tip.BlockNumber),
+					Hash:  tip.BlockHash.String(),
+				},
+				Coins: coins,
+			}, nil
+		}
+		if attempt == coinsAttempts {
+			return nil, wrapError(RpcError, errTipMoved)
+		}
+		tip = after
+	}
+}
+
+// coins lists the live cells locked by lock as coins.
+func (s *AccountAPIService) coins(ctx context.Context, lock *typesCKB.Script) ([]*rosetta.Coin, error) {
+	coins := []*rosetta.Coin{}
+	cursor := ""
+	for {
+		cells, err := s.client.GetCells(ctx, &indexer.SearchKey{
+			Script:     lock,
+			ScriptType: indexer.ScriptTypeLock,
+		}, indexer.SearchOrderAsc, pageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cell := range cells.Objects {
+			coins = append(coins, &rosetta.Coin{
+				CoinIdentifier: &rosetta.CoinIdentifier{
+					Identifier: coinIdentifier(cell.OutPoint.TxHash, cell.OutPoint.Index),
+				},
+				Amount: &types.Amount{
+					Value:    fmt.Sprintf("%d", cell.Output.Capacity),
+					Currency: CkbCurrency,
+				},
+				Metadata: map[string]interface{}{
+					"has_type_script": cell.Output.Type != nil,
+					"output_data_len": len(cell.OutputData),
+				},
+			})
+		}
+
+		if len(cells.Objects) < pageSize {
+			return coins, nil
+		}
+		cursor = cells.LastCursor
+	}
+}
+
+// header resolves a partial block identifier to the header of the canonical block it refers to.
+func (s *AccountAPIService) header(ctx context.Context, identifier *types.PartialBlockIdentifier) (*typesCKB.Header, error) {
+	hash := identifier.Hash != nil && *identifier.Hash != ""
+	if hash && !isHash(*identifier.Hash) {
+		return nil, errInvalidBlockIdentifier
+	}
+	if identifier.Index != nil && *identifier.Index < 0 || !hash && identifier.Index == nil {
+		return nil, errInvalidBlockIdentifier
+	}
+
+	if !hash {
+		header, err := s.client.GetHeaderByNumber(ctx, uint64(*identifier.Index))
+		if err != nil {
+			return nil, err
+		}
+		// the node answers null for an unknown block, which decodes to an empty header
+		if header.Hash == (typesCKB.Hash{}) {
+			return nil, errBlockNotFound
+		}
+		return header, nil
+	}
+
+	header, err := s.client.GetHeader(ctx, typesCKB.HexToHash(*identifier.Hash))
+	if err != nil {
+		return nil, err
+	}
+	if header.Hash == (typesCKB.Hash{}) {
+		return nil, errBlockNotFound
+	}
+	if identifier.Index != nil && *identifier.Index != int64(header.Number) {
+		return nil, errBlockMismatch
+	}
+	canonical, err := s.client.GetHeaderByNumber(ctx, header.Number)
+	if err != nil {
+		return nil, err
+	}
+	if canonical.Hash != header.Hash {
+		return nil, errBlockNotFound
+	}
+
+	return header, nil
+}
+
+// udtBalances adds the amounts of the registered sUDT tokens held by the live cells of lock.
+func (s *AccountAPIService) udtBalances(ctx context.Context, lock *typesCKB.Script, balances *balanceSheet) error {
+	if s.udts == nil || len(s.udts.currencies) == 0 {
+		return nil
+	}
+
+	cursor := ""
+	for {
+		cells, err := s.client.GetCells(ctx, &indexer.SearchKey{
+			Script:     lock,
+			ScriptType: indexer.ScriptTypeLock,
+		}, indexer.SearchOrderAsc, pageSize, cursor)
+		if err != nil {
+			return err
+		}
+
+		for _, cell := range cells.Objects {
+			if currency, amount := s.udts.Amount(cell.Output.Type, cell.OutputData); currency != nil {
+				balances.add(currency, amount)
+			}
+		}
+
+		if len(cells.Objects) < pageSize {
+			return nil
+		}
+		cursor = cells.LastCursor
+	}
+}
+
+// rollbackBalances reverts the balance changes made to lock by the blocks in (from, to], turning
+// balances at block to into balances at block from.
+func (s *AccountAPIService) rollbackBalances(ctx context.Context, lock *typesCKB.Script, from uint64, to uint64, balances *balanceSheet) error {
+	var records []*indexer.Transaction
+	cursor := ""
+	for done := false; !done; {
+		txs, err := s.client.GetTransactions(ctx, &indexer.SearchKey{
+			Script:     lock,
+			ScriptType: indexer.ScriptTypeLock,
+		}, indexer.SearchOrderDesc, pageSize, cursor)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range txs.Objects {
+			if record.BlockNumber <= from {
+				done = true
+				break
+			}
+			if record.BlockNumber <= to {
+				records = append(records, record)
+			}
+		}
+
+		if len(txs.Objects) < pageSize {
+			done = true
+		}
+		cursor = txs.LastCursor
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	hashes := make([]typesCKB.Hash, len(records))
+	for i, record := range records {
+		hashes[i] = record.TxHash
+	}
+	txCache, err := batchTransactions(ctx, s.client, hashes)
+	if err != nil {
+		return err
+	}
+
+	var consumed []*typesCKB.OutPoint
+	for _, record := range records {
+		tx := txCache[record.TxHash.String()].Transaction
+		if record.IoType == indexer.IOTypeIn {
+			consumed = append(consumed, tx.Inputs[record.IoIndex].PreviousOutput)
+			continue
+		}
+		s.applyCell(tx, record.IoIndex, -1, balances)
+	}
+	if len(consumed) == 0 {
+		return nil
+	}
+
+	hashes = make([]typesCKB.Hash, len(consumed))
+	for i, outPoint := range consumed {
+		hashes[i] = outPoint.TxHash
+	}
+	txCache, err = batchTransactions(ctx, s.client, hashes)
+	if err != nil {
+		return err
+	}
+	for _, outPoint := range consumed {
+		s.applyCell(txCache[outPoint.TxHash.String()].Transaction, outPoint.Index, 1, balances)
+	}
+
+	return nil
+}
+
+// applyCell adds the capacity and sUDT amount of output index of tx to balances, negated when sign
+// is negative.
+func (s *AccountAPIService) applyCell(tx *typesCKB.Transaction, index uint, sign int, balances *balanceSheet) {
+	output := tx.Outputs[index]
+	capacity := new(big.Int).SetUint64(output.Capacity)
+	if sign < 0 {
+		capacity.Neg(capacity)
+	}
+	balances.add(CkbCurrency, capacity)
+
+	if currency, amount := s.udts.Amount(output.Type, tx.OutputsData[index]); currency != nil {
+		if sign < 0 {
+			amount.Neg(amount)
+		}
+		balances.add(currency, amount)
+	}
+}
+
+// balanceSheet accumulates balances per currency, keeping the order currencies are first seen in.
+type balanceSheet struct {
+	currencies []*types.Currency
+	values     map[*types.Currency]*big.Int
+}
+
+func newBalanceSheet() *balanceSheet {
+	return &balanceSheet{
+		values: make(map[*types.Currency]*big.Int),
+	}
+}
+
+func (b *balanceSheet) add(currency *types.Currency, amount *big.Int) {
+	if _, ok := b.values[currency]; !ok {
+		b.values[currency] = new(big.Int)
+		b.currencies = append(b.currencies, currency)
+	}
+	b.values[currency].Add(b.values[currency], amount)
+}
+
+func (b *balanceSheet) amounts() []*types.Amount {
+	result := make([]*types.Amount, len(b.currencies))
+	for i, currency := range b.currencies {
+		result[i] = &types.Amount{
+			Value:    b.values[currency].String(),
+			Currency: currency,
+		}
+	}
+	return result
+}
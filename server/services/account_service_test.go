@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+)
+
+func TestAccountBalance(t *testing.T) {
+	historical := int64(3999999)
+	ahead := int64(4000001)
+	unknown := int64(4000002)
+	negative := int64(-1)
+	hash := "0x0a5cd8e40c74b0da5e8d8d9e3bcb5dcdb7e4ed14b1ffd3f3a8e15f14f59e5bd2"
+	orphaned := "0x3e91b07c5d2a48f6b1e0c9d7a3f5268e4b1c0d9a7e6f5432b1a0c9d8e7f6a5b4"
+	malformed := "0x0a5cd8e4"
+	tests := []struct {
+		name       string
+		address    string
+		depth      uint64
+		identifier *types.PartialBlockIdentifier
+		golden     string
+		err        *rosetta.Error
+		cause      error
+	}{
+		{
+			name:    "current balance",
+			address: "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+			golden:  "account_balance_response",
+		},
+		{
+			name:    "final balance",
+			address: "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+			depth:   1,
+			golden:  "account_balance_historical_response",
+		},
+		{
+			name:       "historical balance",
+			address:    "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+			identifier: &types.PartialBlockIdentifier{Index: &historical},
+			golden:     "account_balance_historical_response",
+		},
+		{
+			name:    "invalid address",
+			address: "ckb1invalid",
+			err:     AddressError,
+		},
+		{
+			name:    "fee account",
+			address: FeeAccount.Address,
+			err:     PseudoAccountError,
+		},
+		{
+			name:    "dao account",
+			address: DaoAccount.Address,
+			err:     PseudoAccountError,
+		},
+		{
+			name:       "negative index",
+			address:    "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+			identifier: &types.PartialBlockIdentifier{Index: &negative},
+			err:        BlockIdentifierError,
+		},
+		{
+			name:       "malformed hash",
+			address:    "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+			identifier: &types.PartialBlockIdentifier{Hash: &malformed},
+			err:        BlockIdentifierError,
+		},
+		{
+			name:       "hash and index mismatch",
+			address:    "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+			identifier: &types.PartialBlockIdentifier{Index: &historical, Hash: &hash},
+			err:        BlockNotFoundError,
+		},
+		{
+			name:       "orphaned block",
+			address:    "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+			identifier: &types.PartialBlockIdentifier{Hash: &orphaned},
+			err:        BlockNotFoundError,
+		},
+		{
+			name:       "unknown block",
+			address:    "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+			identifier: &types.PartialBlockIdentifier{Index: &unknown},
+			err:        BlockNotFoundError,
+		},
+		{
+			name:       "block not indexed yet",
+			address:    "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+			identifier: &types.PartialBlockIdentifier{Index: &ahead},
+			err:        RpcError,
+			cause:      errIndexerBehind,
+		},
+	}
+
+	client := newFixtureClient(t, "block", "account", "account_headers")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := NewAccountAPIService(mainnet, client, nil, test.depth)
+			response, err := service.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+				NetworkIdentifier: mainnet,
+				AccountIdentifier: &types.AccountIdentifier{Address: test.address},
+				BlockIdentifier:   test.identifier,
+			})
+			assertError(t, test.err, err)
+			if test.cause != nil && !strings.HasPrefix(err.Details["error"].(string), test.cause.Error()) {
+				t.Errorf("expected cause %v, got %v", test.cause, err.Details["error"])
+			}
+			if test.golden != "" {
+				assertJSON(t, test.golden, response)
+			}
+		})
+	}
+}
+
+func TestAccountCoins(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		golden  string
+		err     *rosetta.Error
+	}{
+		{
+			name:    "live cells",
+			address: "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+			golden:  "account_coins_response",
+		},
+		{
+			name:    "invalid address",
+			address: "ckb1invalid",
+			err:     AddressError,
+		},
+		{
+			name:    "fee account",
+			address: FeeAccount.Address,
+			err:     PseudoAccountError,
+		},
+	}
+
+	service := NewAccountAPIService(mainnet, newFixtureClient(t, "account"), nil, 0)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, err := service.AccountCoins(context.Background(), &rosetta.AccountCoinsRequest{
+				NetworkIdentifier: mainnet,
+				AccountIdentifier: &types.AccountIdentifier{Address: test.address},
+			})
+			assertError(t, test.err, err)
+			if test.golden != "" {
+				assertJSON(t, test.golden, response)
+			}
+		})
+	}
+}
+
+func TestAccountCoinsMovingTip(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves int
+		index int64
+		err   *rosetta.Error
+	}{
+		{
+			name:  "tip moved once",
+			moves: 1,
+			index: 4000001,
+		},
+		{
+			name:  "tip moved before the last attempt",
+			moves: coinsAttempts - 1,
+			index: 4000000 + coinsAttempts - 1,
+		},
+		{
+			name:  "tip kept moving",
+			moves: coinsAttempts,
+			err:   RpcError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the tip advances a block on each call until it has moved test.moves times, the other
+			// calls are answered from the account fixture
+			fixture := newFixtureServer(t, "account")
+			var mu sync.Mutex
+			calls := 0
+			url := serveRpc(t, func(path string, request *rpcRequest) *rpcResponse {
+				if request.Method != "get_tip" {
+					e, err := forward(fixture+path, request)
+					if err != nil {
+						t.Errorf("forward %s: %v", request.Method, err)
+						return &rpcResponse{JSONRPC: "2.0", ID: request.ID, Error: &rpcError{Code: -32000, Message: err.Error()}}
+					}
+					return &rpcResponse{JSONRPC: "2.0", ID: request.ID, Result: e.Result, Error: e.Error}
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				number := 4000000 + calls
+				if calls < test.moves {
+					calls++
+				}
+				result, _ := json.Marshal(map[string]string{
+					"block_hash":   fmt.Sprintf("0x%064x", number),
+					"block_number": fmt.Sprintf("0x%x", number),
+				})
+				return &rpcResponse{JSONRPC: "2.0", ID: request.ID, Result: result}
+			})
+
+			service := NewAccountAPIService(mainnet, dialFixtureClient(t, url), nil, 0)
+			response, err := service.AccountCoins(context.Background(), &rosetta.AccountCoinsRequest{
+				NetworkIdentifier: mainnet,
+				AccountIdentifier: &types.AccountIdentifier{Address: "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd"},
+			})
+			assertError(t, test.err, err)
+			if test.err != nil {
+				return
+			}
+			if response.BlockIdentifier.Index != test.index {
+				t.Errorf("block %d, want %d", response.BlockIdentifier.Index, test.index)
+			}
+			if len(response.Coins) == 0 {
+				t.Errorf("no coins")
+			}
+		})
+	}
+}
@@ -2,25 +2,33 @@ package services
 
 import (
 	"context"
-	"fmt"
 
-	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
-	"github.com/ququzone/ckb-rich-sdk-go/rpc"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	"github.com/ququzone/ckb-sdk-go/rpc"
 	typesCKB "github.com/ququzone/ckb-sdk-go/types"
 )
 
-// BlockAPIService implements the server.BlockAPIServicer interface.
+// BlockAPIService implements the rosetta.BlockAPIServicer interface.
 type BlockAPIService struct {
 	network *types.NetworkIdentifier
-	client  rpc.Client
+	client  ChainClient
+	depth   uint64
+	mapper  *transactionMapper
 }
 
-// NewBlockAPIService creates a new instance of a BlockAPIService.
-func NewBlockAPIService(network *types.NetworkIdentifier, client rpc.Client) server.BlockAPIServicer {
+// NewBlockAPIService creates a new instance of a BlockAPIService. A request without a block
+// identifier is served the block depth blocks below the tip.
+func NewBlockAPIService(network *types.NetworkIdentifier, client ChainClient, udts *UdtRegistry, depth uint64) rosetta.BlockAPIServicer {
 	return &BlockAPIService{
 		network: network,
 		client:  client,
+		depth:   depth,
+		mapper: &transactionMapper{
+			network: network,
+			client:  client,
+			udts:    udts,
+		},
 	}
 }
 
@@ -28,19 +36,16 @@ func NewBlockAPIService(network *types.NetworkIdentifier, client rpc.Client) ser
 func (s *BlockAPIService) Block(
 	ctx context.Context,
 	request *types.BlockRequest,
-) (*types.BlockResponse, *types.Error) {
-	var block *typesCKB.Block
-	var err error
-	if request.BlockIdentifier.Hash == nil || *request.BlockIdentifier.Hash == "" {
-		if *request.BlockIdentifier.Index < 0 {
-			*request.BlockIdentifier.Index = 0
-		}
-		block, err = s.client.GetBlockByNumber(context.Background(), uint64(*request.BlockIdentifier.Index))
-	} else {
-		block, err = s.client.GetBlock(context.Background(), typesCKB.HexToHash(*request.BlockIdentifier.Hash))
+) (*types.BlockResponse, *rosetta.Error) {
+	block, err := s.block(ctx, request.BlockIdentifier)
+	if err == errInvalidBlockIdentifier {
+		return nil, BlockIdentifierError
+	}
+	if err == errBlockNotFound {
+		return nil, BlockNotFoundError
 	}
 	if err != nil {
-		return nil, RpcError
+		return nil, wrapError(RpcError, err)
 	}
 
 	result := &types.BlockResponse{
@@ -65,47 +70,9 @@ func (s *BlockAPIService) Block(
 		}
 	}
 
-	batchReq := make([]typesCKB.BatchTransactionItem, 0)
-	txHashCache := make(map[string]bool)
-	for i, tx := range block.Transactions {
-		if i != 0 {
-			for _, input := range tx.Inputs {
-				if _, ok := txHashCache[input.PreviousOutput.TxHash.String()]; !ok {
-					txHashCache[input.PreviousOutput.TxHash.String()] = true
-					batchReq = append(batchReq, typesCKB.BatchTransactionItem{
-						Hash:   input.PreviousOutput.TxHash,
-						Result: &typesCKB.TransactionWithStatus{},
-					})
-				}
-			}
-		}
-	}
-	inputTxCache := make(map[string]*typesCKB.TransactionWithStatus)
-	if len(batchReq) > 0 {
-		count := len(batchReq) / 2000
-		if len(batchReq)%2000 != 0 {
-			count++
-		}
-
-		for i := 0; i < count; i++ {
-			start := i * 2000
-			end := start + 2000
-			if i == count-1 {
-				end = len(batchReq)
-			}
-			err = s.client.BatchTransactions(context.Background(), batchReq[start:end])
-			if err != nil {
-				return nil, RpcError
-			}
-
-		}
-	}
-
-	for _, req := range batchReq {
-		if req.Error != nil || req.Result.Transaction == nil {
-			return nil, RpcError
-		}
-		inputTxCache[req.Hash.String()] = req.Result
+	inputTxCache, err := fetchInputTransactions(ctx, s.client, block.Transactions[1:])
+	if err != nil {
+		return nil, wrapError(RpcError, err)
 	}
 
 	for i, tx := range block.Transactions {
@@ -119,22 +86,9 @@ func (s *BlockAPIService) Block(
 					},
 					Operations: []*types.Operation{},
 				}
-				for _, output := range tx.Outputs {
-					transaction.Operations = append(transaction.Operations, &types.Operation{
-						OperationIdentifier: &types.OperationIdentifier{
-							Index: optIndex,
-						},
-						Type:   "Reward",
-						Status: "Success",
-						Account: &types.AccountIdentifier{
-							Address: GenerateAddress(s.network, output.Lock),
-						},
-						Amount: &types.Amount{
-							Value:    fmt.Sprintf("%d", output.Capacity),
-							Currency: CkbCurrency,
-						},
-					})
-					optIndex++
+				_, err = s.mapper.processCellbase(ctx, block.Header.Hash, tx, optIndex, transaction)
+				if err != nil {
+					return nil, wrapError(RpcError, err)
 				}
 			}
 		} else {
@@ -144,44 +98,9 @@ func (s *BlockAPIService) Block(
 				},
 				Operations: []*types.Operation{},
 			}
-			for _, input := range tx.Inputs {
-				tx := inputTxCache[input.PreviousOutput.TxHash.String()]
-				if tx == nil {
-					return nil, ServerError
-				}
-
-				transaction.Operations = append(transaction.Operations, &types.Operation{
-					OperationIdentifier: &types.OperationIdentifier{
-						Index: optIndex,
-					},
-					Type:   "Transfer",
-					Status: "Success",
-					Account: &types.AccountIdentifier{
-						Address: GenerateAddress(s.network, tx.Transaction.Outputs[input.PreviousOutput.Index].Lock),
-					},
-					Amount: &types.Amount{
-						Value:    fmt.Sprintf("-%d", tx.Transaction.Outputs[input.PreviousOutput.Index].Capacity),
-						Currency: CkbCurrency,
-					},
-				})
-				optIndex++
-			}
-			for _, output := range tx.Outputs {
-				transaction.Operations = append(transaction.Operations, &types.Operation{
-					OperationIdentifier: &types.OperationIdentifier{
-						Index: optIndex,
-					},
-					Type:   "Transfer",
-					Status: "Success",
-					Account: &types.AccountIdentifier{
-						Address: GenerateAddress(s.network, output.Lock),
-					},
-					Amount: &types.Amount{
-						Value:    fmt.Sprintf("%d", output.Capacity),
-						Currency: CkbCurrency,
-					},
-				})
-				optIndex++
+			_, err = s.mapper.processTransaction(ctx, tx, inputTxCache, optIndex, transaction)
+			if err != nil {
+				return nil, wrapError(RpcError, err)
 			}
 		}
 		if transaction != nil {
@@ -189,17 +108,95 @@ func (s *BlockAPIService) Block(
 		}
 	}
 
+	// the block may have been orphaned by a reorganization while its transactions were fetched
+	canonical, err := s.canonical(ctx, block.Header)
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+	if !canonical {
+		return nil, BlockNotFoundError
+	}
+
 	return result, nil
 }
 
+// block fetches the block identified by identifier, which must be on the canonical chain when
+// identified by hash. The hash and index must agree when both are given, the latest block is
+// fetched when neither is.
+func (s *BlockAPIService) block(ctx context.Context, identifier *types.PartialBlockIdentifier) (*typesCKB.Block, error) {
+	if identifier == nil {
+		identifier = &types.PartialBlockIdentifier{}
+	}
+	hash := identifier.Hash != nil && *identifier.Hash != ""
+	if hash && !isHash(*identifier.Hash) {
+		return nil, errInvalidBlockIdentifier
+	}
+	if identifier.Index != nil && *identifier.Index < 0 {
+		return nil, errInvalidBlockIdentifier
+	}
+
+	var block *typesCKB.Block
+	var err error
+	switch {
+	case hash:
+		block, err = s.client.GetBlock(ctx, typesCKB.HexToHash(*identifier.Hash))
+	case identifier.Index != nil:
+		block, err = s.client.GetBlockByNumber(ctx, uint64(*identifier.Index))
+	default:
+		var tip *typesCKB.Header
+		tip, err = s.client.GetTipHeader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		block, err = s.client.GetBlockByNumber(ctx, finalBlock(tip.Number, s.depth))
+	}
+	if err == rpc.NotFound {
+		return nil, errBlockNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the node answers null for an unknown block, which decodes to an empty block
+	if block.Header == nil || block.Header.Hash == (typesCKB.Hash{}) {
+		return nil, errBlockNotFound
+	}
+	if identifier.Index != nil && *identifier.Index != int64(block.Header.Number) {
+		return nil, errBlockNotFound
+	}
+
+	canonical, err := s.canonical(ctx, block.Header)
+	if err != nil {
+		return nil, err
+	}
+	if !canonical {
+		return nil, errBlockNotFound
+	}
+
+	return block, nil
+}
+
+// canonical reports whether header is the header of the canonical chain at its height.
+func (s *BlockAPIService) canonical(ctx context.Context, header *typesCKB.Header) (bool, error) {
+	current, err := s.client.GetHeaderByNumber(ctx, header.Number)
+	if err != nil {
+		return false, err
+	}
+
+	return current.Hash == header.Hash, nil
+}
+
 // BlockTransaction implements the /block/transaction endpoint.
 func (s *BlockAPIService) BlockTransaction(
 	ctx context.Context,
 	request *types.BlockTransactionRequest,
-) (*types.BlockTransactionResponse, *types.Error) {
-	tx, err := s.client.GetTransaction(context.Background(), typesCKB.HexToHash(request.TransactionIdentifier.Hash))
+) (*types.BlockTransactionResponse, *rosetta.Error) {
+	tx, err := s.client.GetTransaction(ctx, typesCKB.HexToHash(request.TransactionIdentifier.Hash))
 	if err != nil {
-		return nil, RpcError
+		return nil, wrapError(RpcError, err)
+	}
+	// the node returns no transaction for an unknown hash, which the client decodes as an empty one
+	if tx.Transaction == nil || len(tx.Transaction.Inputs) == 0 {
+		return nil, TransactionNotFoundError
 	}
 	var transaction *types.Transaction
 	optIndex := int64(0)
@@ -211,22 +208,12 @@ func (s *BlockAPIService) BlockTransaction(
 				},
 				Operations: []*types.Operation{},
 			}
-			for _, output := range tx.Transaction.Outputs {
-				transaction.Operations = append(transaction.Operations, &types.Operation{
-					OperationIdentifier: &types.OperationIdentifier{
-						Index: optIndex,
-					},
-					Type:   "Reward",
-					Status: "Success",
-					Account: &types.AccountIdentifier{
-						Address: GenerateAddress(s.network, output.Lock),
-					},
-					Amount: &types.Amount{
-						Value:    fmt.Sprintf("%d", output.Capacity),
-						Currency: CkbCurrency,
-					},
-				})
-				optIndex++
+			if tx.TxStatus == nil || tx.TxStatus.BlockHash == nil {
+				return nil, ServerError
+			}
+			_, err = s.mapper.processCellbase(ctx, *tx.TxStatus.BlockHash, tx.Transaction, optIndex, transaction)
+			if err != nil {
+				return nil, wrapError(RpcError, err)
 			}
 		}
 	} else {
@@ -236,28 +223,13 @@ func (s *BlockAPIService) BlockTransaction(
 			},
 			Operations: []*types.Operation{},
 		}
-		index, err := s.processTxInputs(tx.Transaction.Inputs, optIndex, transaction)
+		inputTxCache, err := fetchInputTransactions(ctx, s.client, []*typesCKB.Transaction{tx.Transaction})
 		if err != nil {
-			return nil, RpcError
+			return nil, wrapError(RpcError, err)
 		}
-		optIndex = index
-
-		for _, output := range tx.Transaction.Outputs {
-			transaction.Operations = append(transaction.Operations, &types.Operation{
-				OperationIdentifier: &types.OperationIdentifier{
-					Index: optIndex,
-				},
-				Type:   "Transfer",
-				Status: "Success",
-				Account: &types.AccountIdentifier{
-					Address: GenerateAddress(s.network, output.Lock),
-				},
-				Amount: &types.Amount{
-					Value:    fmt.Sprintf("%d", output.Capacity),
-					Currency: CkbCurrency,
-				},
-			})
-			optIndex++
+		_, err = s.mapper.processTransaction(ctx, tx.Transaction, inputTxCache, optIndex, transaction)
+		if err != nil {
+			return nil, wrapError(RpcError, err)
 		}
 	}
 
@@ -274,42 +246,3 @@ func (s *BlockAPIService) BlockTransaction(
 		Transaction: transaction,
 	}, nil
 }
-
-func (s *BlockAPIService) processTxInputs(inputs []*typesCKB.CellInput, optIndex int64, transaction *types.Transaction) (int64, error) {
-	batchReq := make([]typesCKB.BatchTransactionItem, len(inputs))
-	for i, input := range inputs {
-		batchReq[i] = typesCKB.BatchTransactionItem{
-			Hash:   input.PreviousOutput.TxHash,
-			Result: &typesCKB.TransactionWithStatus{},
-		}
-	}
-
-	err := s.client.BatchTransactions(context.Background(), batchReq)
-	if err != nil {
-		return 0, err
-	}
-
-	for i, input := range inputs {
-		req := batchReq[i]
-		if req.Error != nil {
-			return 0, req.Error
-		}
-		transaction.Operations = append(transaction.Operations, &types.Operation{
-			OperationIdentifier: &types.OperationIdentifier{
-				Index: optIndex,
-			},
-			Type:   "Transfer",
-			Status: "Success",
-			Account: &types.AccountIdentifier{
-				Address: GenerateAddress(s.network, req.Result.Transaction.Outputs[input.PreviousOutput.Index].Lock),
-			},
-			Amount: &types.Amount{
-				Value:    fmt.Sprintf("-%d", req.Result.Transaction.Outputs[input.PreviousOutput.Index].Capacity),
-				Currency: CkbCurrency,
-			},
-		})
-		optIndex++
-	}
-
-	return optIndex, nil
-}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	"github.com/ququzone/ckb-sdk-go/address"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+// reorgClient reorganizes the chain once the canonical header has been fetched a number of times.
+type reorgClient struct {
+	ChainClient
+	remaining int
+}
+
+func (c *reorgClient) GetHeaderByNumber(ctx context.Context, number uint64) (*typesCKB.Header, error) {
+	header, err := c.ChainClient.GetHeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if c.remaining > 0 {
+		c.remaining--
+		return header, nil
+	}
+
+	return &typesCKB.Header{
+		Number:     header.Number,
+		Hash:       typesCKB.BytesToHash([]byte{1}),
+		ParentHash: header.ParentHash,
+	}, nil
+}
+
+func TestBlock(t *testing.T) {
+	index := int64(4000000)
+	parent := int64(3999999)
+	unknown := int64(4000001)
+	missing := int64(1)
+	negative := int64(-1)
+	hash := "0x0a5cd8e40c74b0da5e8d8d9e3bcb5dcdb7e4ed14b1ffd3f3a8e15f14f59e5bd2"
+	malformed := "0x0a5cd8e4"
+	tests := []struct {
+		name       string
+		identifier *types.PartialBlockIdentifier
+		reorg      bool
+		fetched    int
+		golden     string
+		err        *rosetta.Error
+	}{
+		{
+			name:       "by index",
+			identifier: &types.PartialBlockIdentifier{Index: &index},
+			golden:     "block_response",
+		},
+		{
+			name:       "by hash",
+			identifier: &types.PartialBlockIdentifier{Hash: &hash},
+			golden:     "block_response",
+		},
+		{
+			name:       "by hash and index",
+			identifier: &types.PartialBlockIdentifier{Index: &index, Hash: &hash},
+			golden:     "block_response",
+		},
+		{
+			name:   "latest without identifier",
+			golden: "block_response",
+		},
+		{
+			name:       "latest with empty identifier",
+			identifier: &types.PartialBlockIdentifier{},
+			golden:     "block_response",
+		},
+		{
+			name:       "negative index",
+			identifier: &types.PartialBlockIdentifier{Index: &negative},
+			err:        BlockIdentifierError,
+		},
+		{
+			name:       "malformed hash",
+			identifier: &types.PartialBlockIdentifier{Hash: &malformed},
+			err:        BlockIdentifierError,
+		},
+		{
+			name:       "hash and index mismatch",
+			identifier: &types.PartialBlockIdentifier{Index: &parent, Hash: &hash},
+			err:        BlockNotFoundError,
+		},
+		{
+			name:       "unknown block",
+			identifier: &types.PartialBlockIdentifier{Index: &unknown},
+			err:        BlockNotFoundError,
+		},
+		{
+			name:       "orphaned by hash",
+			identifier: &types.PartialBlockIdentifier{Hash: &hash},
+			reorg:      true,
+			err:        BlockNotFoundError,
+		},
+		{
+			name:       "orphaned while converted",
+			identifier: &types.PartialBlockIdentifier{Index: &index},
+			reorg:      true,
+			fetched:    1,
+			err:        BlockNotFoundError,
+		},
+		{
+			name:       "node error",
+			identifier: &types.PartialBlockIdentifier{Index: &missing},
+			err:        RpcError,
+		},
+	}
+
+	// the fixture tip is 4000002, two blocks above the latest block served
+	client := newFixtureClient(t, "block")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := NewBlockAPIService(mainnet, client, nil, 2)
+			if test.reorg {
+				service = NewBlockAPIService(mainnet, &reorgClient{ChainClient: client, remaining: test.fetched}, nil, 2)
+			}
+			response, err := service.Block(context.Background(), &types.BlockRequest{
+				NetworkIdentifier: mainnet,
+				BlockIdentifier:   test.identifier,
+			})
+			assertError(t, test.err, err)
+			if test.golden != "" {
+				assertJSON(t, test.golden, response)
+			}
+		})
+	}
+}
+
+func TestBlockTransaction(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     string
+		golden   string
+		balanced bool
+		coins    map[string]string
+		err      *rosetta.Error
+	}{
+		{
+			name:     "transfer and dao deposit",
+			hash:     "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+			golden:   "block_transaction_response",
+			balanced: true,
+		},
+		{
+			name:   "cellbase",
+			hash:   "0x9cb587aadf6cee9680ec744c85c11578086930983435d5ca8bb155f35bfbeda2",
+			golden: "block_transaction_cellbase_response",
+			coins: map[string]string{
+				"0x9cb587aadf6cee9680ec744c85c11578086930983435d5ca8bb155f35bfbeda2:0": "197905865470",
+			},
+		},
+		{
+			name:     "dao withdraw",
+			hash:     "0x34e85f1e01693a71b6bd38f2f7648eabc9aa5d8c6866496e236fa9ff5d0cb156",
+			golden:   "block_transaction_dao_withdraw_response",
+			balanced: true,
+		},
+		{
+			name: "unknown transaction",
+			hash: "0x0000000000000000000000000000000000000000000000000000000000000001",
+			err:  TransactionNotFoundError,
+		},
+	}
+
+	service := NewBlockAPIService(mainnet, newFixtureClient(t, "block", "dao_withdraw"), nil, 0)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, err := service.BlockTransaction(context.Background(), &types.BlockTransactionRequest{
+				NetworkIdentifier: mainnet,
+				BlockIdentifier: &types.BlockIdentifier{
+					Index: 4000000,
+					Hash:  "0x0a5cd8e40c74b0da5e8d8d9e3bcb5dcdb7e4ed14b1ffd3f3a8e15f14f59e5bd2",
+				},
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: test.hash},
+			})
+			assertError(t, test.err, err)
+			if test.golden != "" {
+				assertJSON(t, test.golden, response)
+			}
+			if test.balanced {
+				assertBalanced(t, response.Transaction)
+			}
+			for coin, amount := range test.coins {
+				if actual := coinAmount(t, response.Transaction, coin); actual != amount {
+					t.Errorf("expected coin %s of %s, got %s", coin, amount, actual)
+				}
+			}
+		})
+	}
+}
+
+// assertBalanced fails t unless the CKB operations of transaction, which is not a cellbase, sum to
+// zero, the fee and DAO compensation being accounted to pseudo accounts.
+func assertBalanced(t *testing.T, transaction *types.Transaction) {
+	t.Helper()
+	sum := new(big.Int)
+	for _, operation := range transaction.Operations {
+		if operation.Amount.Currency.Symbol != CkbCurrency.Symbol {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(operation.Amount.Value, 10)
+		if !ok {
+			t.Fatalf("invalid amount %s", operation.Amount.Value)
+		}
+		sum.Add(sum, amount)
+
+		// a reconciler looks up the balance of every account but the exempt pseudo accounts
+		if _, err := address.Parse(operation.Account.Address); err != nil && !isPseudoAccount(operation.Account.Address) {
+			t.Errorf("operation %d is on account %s, neither an address nor exempt", operation.OperationIdentifier.Index, operation.Account.Address)
+		}
+	}
+	if sum.Sign() != 0 {
+		t.Errorf("operations of %s sum to %s", transaction.TransactionIdentifier.Hash, sum)
+	}
+}
+
+// coinAmount returns the amount of the coin created by transaction with identifier, which is the
+// sum of the amounts of the operation recording its creation and of the operations related to it.
+func coinAmount(t *testing.T, transaction *types.Transaction, identifier string) string {
+	t.Helper()
+	for _, operation := range transaction.Operations {
+		change, ok := operation.Metadata["coin_change"].(*rosetta.CoinChange)
+		if !ok || change.CoinIdentifier.Identifier != identifier {
+			continue
+		}
+		sum := new(big.Int)
+		related := append([]*types.OperationIdentifier{operation.OperationIdentifier}, operation.RelatedOperations...)
+		for _, id := range related {
+			amount, ok := new(big.Int).SetString(transaction.Operations[id.Index].Amount.Value, 10)
+			if !ok {
+				t.Fatalf("invalid amount %s", transaction.Operations[id.Index].Amount.Value)
+			}
+			sum.Add(sum, amount)
+		}
+		return sum.String()
+	}
+
+	t.Fatalf("coin %s is not created by %s", identifier, transaction.TransactionIdentifier.Hash)
+	return ""
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethRpc "github.com/ethereum/go-ethereum/rpc"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+// CellStatus looks up whether cells are live on a CKB node. The rich node rpc.Client fails on
+// cells which are not live, as the node returns no cell for them.
+type CellStatus interface {
+	// GetCellStatus returns the status of the cell at outPoint: live, dead or unknown.
+	GetCellStatus(ctx context.Context, outPoint *typesCKB.OutPoint) (string, error)
+	// GetCellOutput returns the status of the cell at outPoint and its output, which is nil unless
+	// the cell is live.
+	GetCellOutput(ctx context.Context, outPoint *typesCKB.OutPoint) (*typesCKB.CellOutput, string, error)
+}
+
+type cellStatus struct {
+	c *ethRpc.Client
+}
+
+// NewCellStatus creates a CellStatus calling the node rpc through c.
+func NewCellStatus(c *ethRpc.Client) CellStatus {
+	return &cellStatus{c}
+}
+
+func (s *cellStatus) GetCellStatus(ctx context.Context, point *typesCKB.OutPoint) (string, error) {
+	_, status, err := s.GetCellOutput(ctx, point)
+
+	return status, err
+}
+
+func (s *cellStatus) GetCellOutput(ctx context.Context, point *typesCKB.OutPoint) (*typesCKB.CellOutput, string, error) {
+	var result struct {
+		Cell *struct {
+			Output cellOutput `json:"output"`
+		} `json:"cell"`
+		Status string `json:"status"`
+	}
+
+	err := s.c.CallContext(ctx, &result, "get_live_cell", outPoint{
+		TxHash: point.TxHash,
+		Index:  hexutil.Uint(point.Index),
+	}, false)
+	if err != nil {
+		return nil, "", err
+	}
+	if result.Cell == nil || result.Status != "live" {
+		return nil, result.Status, nil
+	}
+
+	return toOutputs([]cellOutput{result.Cell.Output})[0], result.Status, nil
+}
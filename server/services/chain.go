@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ququzone/ckb-rich-sdk-go/indexer"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+// ChainClient is the part of the rich node rpc.Client the services depend on.
+type ChainClient interface {
+	GetTipHeader(ctx context.Context) (*typesCKB.Header, error)
+	GetBlock(ctx context.Context, hash typesCKB.Hash) (*typesCKB.Block, error)
+	GetBlockByNumber(ctx context.Context, number uint64) (*typesCKB.Block, error)
+	GetHeader(ctx context.Context, hash typesCKB.Hash) (*typesCKB.Header, error)
+	GetHeaderByNumber(ctx context.Context, number uint64) (*typesCKB.Header, error)
+	GetTransaction(ctx context.Context, hash typesCKB.Hash) (*typesCKB.TransactionWithStatus, error)
+	BatchTransactions(ctx context.Context, batch []typesCKB.BatchTransactionItem) error
+	GetLiveCell(ctx context.Context, outPoint *typesCKB.OutPoint, withData bool) (*typesCKB.CellWithStatus, error)
+	GetCellbaseOutputCapacityDetails(ctx context.Context, hash typesCKB.Hash) (*typesCKB.BlockReward, error)
+	CalculateDaoMaximumWithdraw(ctx context.Context, point *typesCKB.OutPoint, hash typesCKB.Hash) (uint64, error)
+	DryRunTransaction(ctx context.Context, tx *typesCKB.Transaction) (*typesCKB.DryRunTransactionResult, error)
+	SendTransaction(ctx context.Context, tx *typesCKB.Transaction) (*typesCKB.Hash, error)
+	LocalNodeInfo(ctx context.Context) (*typesCKB.Node, error)
+
+	GetTip(ctx context.Context) (*indexer.TipHeader, error)
+	GetCellsCapacity(ctx context.Context, searchKey *indexer.SearchKey) (*indexer.Capacity, error)
+	GetCells(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.LiveCells, error)
+	GetTransactions(ctx context.Context, searchKey *indexer.SearchKey, order indexer.SearchOrder, limit uint64, afterCursor string) (*indexer.Transactions, error)
+}
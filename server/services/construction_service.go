@@ -1,35 +1,402 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 
-	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
-	"github.com/ququzone/ckb-rich-sdk-go/rpc"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	"github.com/ququzone/ckb-sdk-go/address"
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	transactionCKB "github.com/ququzone/ckb-sdk-go/transaction"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
 )
 
-// ConstructionAPIService implements the server.ConstructionAPIServicer interface.
+// ConstructionAPIService implements the rosetta.ConstructionAPIServicer interface.
 type ConstructionAPIService struct {
 	network *types.NetworkIdentifier
-	client  rpc.Client
+	client  ChainClient
+	cells   CellStatus
+	dryRun  bool
 }
 
-// NewConstructionAPIService creates a new instance of a ConstructionAPIService.
-func NewConstructionAPIService(network *types.NetworkIdentifier, client rpc.Client) server.ConstructionAPIServicer {
+// NewConstructionAPIService creates a new instance of a ConstructionAPIService. With dryRun, a
+// submitted transaction is first run by the node and the cycles it consumes are reported.
+func NewConstructionAPIService(
+	network *types.NetworkIdentifier,
+	client ChainClient,
+	cells CellStatus,
+	dryRun bool,
+) rosetta.ConstructionAPIServicer {
 	return &ConstructionAPIService{
 		network: network,
 		client:  client,
+		cells:   cells,
+		dryRun:  dryRun,
 	}
 }
 
+// ConstructionDerive implements the /construction/derive endpoint.
+func (s *ConstructionAPIService) ConstructionDerive(
+	ctx context.Context,
+	request *rosetta.ConstructionDeriveRequest,
+) (*rosetta.ConstructionDeriveResponse, *rosetta.Error) {
+	if request.PublicKey == nil || request.PublicKey.CurveType != rosetta.CurveTypeSecp256k1 {
+		return nil, PublicKeyError
+	}
+	pubKey, err := hexutil.Decode(request.PublicKey.HexBytes)
+	if err != nil {
+		return nil, wrapError(PublicKeyError, err)
+	}
+	addr, lock, err := Secp256k1Address(s.network, pubKey)
+	if err != nil {
+		return nil, wrapError(PublicKeyError, err)
+	}
+
+	metadata := map[string]interface{}{}
+	err = convertMetadata(&deriveMetadata{
+		Lock: &script{
+			CodeHash: lock.CodeHash,
+			HashType: lock.HashType,
+			Args:     lock.Args,
+		},
+	}, &metadata)
+	if err != nil {
+		return nil, wrapError(ServerError, err)
+	}
+
+	return &rosetta.ConstructionDeriveResponse{
+		Address:  addr,
+		Metadata: metadata,
+	}, nil
+}
+
+// deriveMetadata is the metadata returned by /construction/derive.
+type deriveMetadata struct {
+	Lock *script `json:"lock"`
+}
+
+// ConstructionPreprocess implements the /construction/preprocess endpoint.
+func (s *ConstructionAPIService) ConstructionPreprocess(
+	ctx context.Context,
+	request *rosetta.ConstructionPreprocessRequest,
+) (*rosetta.ConstructionPreprocessResponse, *rosetta.Error) {
+	intent, err := s.parseIntent(request.Operations)
+	if err != nil {
+		return nil, wrapError(OperationError, err)
+	}
+
+	inputs := make([]string, len(intent.inputs))
+	for i, input := range intent.inputs {
+		inputs[i] = coinIdentifier(input.TxHash, input.Index)
+	}
+
+	return &rosetta.ConstructionPreprocessResponse{
+		Options: map[string]interface{}{
+			"inputs": inputs,
+		},
+	}, nil
+}
+
 // ConstructionMetadata implements the /construction/metadata endpoint.
 func (s *ConstructionAPIService) ConstructionMetadata(
-	context.Context,
-	*types.ConstructionMetadataRequest,
-) (*types.ConstructionMetadataResponse, *types.Error) {
+	ctx context.Context,
+	request *types.ConstructionMetadataRequest,
+) (*types.ConstructionMetadataResponse, *rosetta.Error) {
+	var options struct {
+		Inputs []string `json:"inputs"`
+	}
+	if err := convertMetadata(request.Options, &options); err != nil {
+		return nil, OperationError
+	}
+
+	inputCells := make([]*typesCKB.CellOutput, len(options.Inputs))
+	for i, input := range options.Inputs {
+		outPoint, err := parseCoinIdentifier(input)
+		if err != nil {
+			return nil, wrapError(OperationError, err)
+		}
+		// the client fails on cells which are not live, the node returning no cell for them
+		output, status, err := s.cells.GetCellOutput(ctx, outPoint)
+		if err != nil {
+			return nil, wrapError(RpcError, err)
+		}
+		if output == nil {
+			return nil, wrapError(OperationError, fmt.Errorf("input %s is %s", input, status))
+		}
+		inputCells[i] = output
+	}
+
+	genesis, err := s.client.GetBlockByNumber(ctx, 0)
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+	if len(genesis.Transactions) < 2 {
+		return nil, ServerError
+	}
+
+	// the secp256k1 blake160 dep group is the first output of the second genesis transaction
+	metadata := map[string]interface{}{}
+	err = convertMetadata(&constructionMetadata{
+		CellDeps: fromCellDeps([]*typesCKB.CellDep{
+			{
+				OutPoint: &typesCKB.OutPoint{
+					TxHash: genesis.Transactions[1].Hash,
+					Index:  0,
+				},
+				DepType: typesCKB.DepTypeDepGroup,
+			},
+		}),
+		InputCells: fromOutputs(inputCells),
+	}, &metadata)
+	if err != nil {
+		return nil, wrapError(ServerError, err)
+	}
+
 	return &types.ConstructionMetadataResponse{
-		Metadata: map[string]interface{}{},
+		Metadata: metadata,
+	}, nil
+}
+
+// ConstructionPayloads implements the /construction/payloads endpoint.
+func (s *ConstructionAPIService) ConstructionPayloads(
+	ctx context.Context,
+	request *rosetta.ConstructionPayloadsRequest,
+) (*rosetta.ConstructionPayloadsResponse, *rosetta.Error) {
+	intent, err := s.parseIntent(request.Operations)
+	if err != nil {
+		return nil, wrapError(OperationError, err)
+	}
+
+	var metadata constructionMetadata
+	if err := convertMetadata(request.Metadata, &metadata); err != nil {
+		return nil, OperationError
+	}
+	inputCells := toOutputs(metadata.InputCells)
+	if len(inputCells) != len(intent.inputs) {
+		return nil, OperationError
+	}
+	for i, cell := range inputCells {
+		if cell.Capacity != intent.inputCapacities[i] || !cell.Lock.Equals(intent.inputLocks[i]) || cell.Type != nil {
+			return nil, OperationError
+		}
+	}
+	if err := checkCapacities(intent); err != nil {
+		return nil, wrapError(OperationError, err)
+	}
+
+	tx := &typesCKB.Transaction{
+		Version:     0,
+		CellDeps:    toCellDeps(metadata.CellDeps),
+		HeaderDeps:  []typesCKB.Hash{},
+		Inputs:      make([]*typesCKB.CellInput, len(intent.inputs)),
+		Outputs:     intent.outputs,
+		OutputsData: make([][]byte, len(intent.outputs)),
+		Witnesses:   make([][]byte, len(intent.inputs)),
+	}
+	for i, input := range intent.inputs {
+		tx.Inputs[i] = &typesCKB.CellInput{
+			Since:          0,
+			PreviousOutput: input,
+		}
+		tx.Witnesses[i] = []byte{}
+	}
+	for i := range tx.OutputsData {
+		tx.OutputsData[i] = []byte{}
+	}
+
+	groups, err := signingGroups(inputCells)
+	if err != nil {
+		return nil, wrapError(OperationError, err)
+	}
+	placeholder, err := transactionCKB.EmptyWitnessArg.Serialize()
+	if err != nil {
+		return nil, wrapError(ServerError, err)
+	}
+	for _, group := range groups {
+		tx.Witnesses[group[0]] = placeholder
+	}
+
+	payloads := make([]*rosetta.SigningPayload, len(groups))
+	for i, group := range groups {
+		message, err := signingMessage(tx, group)
+		if err != nil {
+			return nil, wrapError(ServerError, err)
+		}
+		payloads[i] = &rosetta.SigningPayload{
+			Address:       GenerateAddress(s.network, inputCells[group[0]].Lock),
+			HexBytes:      hexutil.Encode(message),
+			SignatureType: rosetta.SignatureTypeEcdsaRecovery,
+		}
+	}
+
+	unsigned, err := fromConstructionTransaction(tx, inputCells)
+	if err != nil {
+		return nil, wrapError(ServerError, err)
+	}
+
+	return &rosetta.ConstructionPayloadsResponse{
+		UnsignedTransaction: unsigned,
+		Payloads:            payloads,
+	}, nil
+}
+
+// ConstructionParse implements the /construction/parse endpoint.
+func (s *ConstructionAPIService) ConstructionParse(
+	ctx context.Context,
+	request *rosetta.ConstructionParseRequest,
+) (*rosetta.ConstructionParseResponse, *rosetta.Error) {
+	tx, inputCells, err := toConstructionTransaction(request.Transaction)
+	if err != nil {
+		return nil, wrapError(TransactionError, err)
+	}
+	if len(inputCells) != len(tx.Inputs) {
+		return nil, wrapError(TransactionError, fmt.Errorf("%d input cells for %d inputs", len(inputCells), len(tx.Inputs)))
+	}
+
+	operations := make([]*types.Operation, 0, len(tx.Inputs)+len(tx.Outputs))
+	for i, input := range tx.Inputs {
+		operations = append(operations, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: int64(len(operations)),
+			},
+			Type: "Transfer",
+			Account: &types.AccountIdentifier{
+				Address: GenerateAddress(s.network, inputCells[i].Lock),
+			},
+			Amount: &types.Amount{
+				Value:    fmt.Sprintf("-%d", inputCells[i].Capacity),
+				Currency: CkbCurrency,
+			},
+			Metadata: coinChange(input.PreviousOutput.TxHash, input.PreviousOutput.Index, rosetta.CoinSpent),
+		})
+	}
+	for _, output := range tx.Outputs {
+		operations = append(operations, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: int64(len(operations)),
+			},
+			Type: "Transfer",
+			Account: &types.AccountIdentifier{
+				Address: GenerateAddress(s.network, output.Lock),
+			},
+			Amount: &types.Amount{
+				Value:    fmt.Sprintf("%d", output.Capacity),
+				Currency: CkbCurrency,
+			},
+		})
+	}
+
+	signers := []string{}
+	if request.Signed {
+		groups, err := signingGroups(inputCells)
+		if err != nil {
+			return nil, wrapError(TransactionError, err)
+		}
+		for _, group := range groups {
+			signers = append(signers, GenerateAddress(s.network, inputCells[group[0]].Lock))
+		}
+	}
+
+	return &rosetta.ConstructionParseResponse{
+		Operations: operations,
+		Signers:    signers,
+	}, nil
+}
+
+// ConstructionCombine implements the /construction/combine endpoint.
+func (s *ConstructionAPIService) ConstructionCombine(
+	ctx context.Context,
+	request *rosetta.ConstructionCombineRequest,
+) (*rosetta.ConstructionCombineResponse, *rosetta.Error) {
+	tx, inputCells, err := toConstructionTransaction(request.UnsignedTransaction)
+	if err != nil {
+		return nil, wrapError(TransactionError, err)
+	}
+	if len(inputCells) != len(tx.Inputs) {
+		return nil, wrapError(TransactionError, fmt.Errorf("%d input cells for %d inputs", len(inputCells), len(tx.Inputs)))
+	}
+	if len(tx.Witnesses) < len(tx.Inputs) {
+		return nil, wrapError(TransactionError, fmt.Errorf("%d witnesses for %d inputs", len(tx.Witnesses), len(tx.Inputs)))
+	}
+
+	groups, err := signingGroups(inputCells)
+	if err != nil {
+		return nil, wrapError(TransactionError, err)
+	}
+
+	witnesses := make([][]byte, len(tx.Witnesses))
+	copy(witnesses, tx.Witnesses)
+	for _, group := range groups {
+		message, err := signingMessage(tx, group)
+		if err != nil {
+			return nil, wrapError(TransactionError, err)
+		}
+
+		var signature []byte
+		for _, sig := range request.Signatures {
+			if sig != nil && sig.SigningPayload != nil && sig.SigningPayload.HexBytes == hexutil.Encode(message) {
+				signature, err = hexutil.Decode(sig.HexBytes)
+				if err != nil {
+					return nil, wrapError(SignatureError, err)
+				}
+				break
+			}
+		}
+		if len(signature) != 65 {
+			return nil, SignatureError
+		}
+
+		pubKey, err := crypto.SigToPub(message, signature)
+		if err != nil {
+			return nil, wrapError(SignatureError, err)
+		}
+		args, err := blake2b.Blake160(crypto.CompressPubkey(pubKey))
+		if err != nil || !bytes.Equal(args, inputCells[group[0]].Lock.Args) {
+			return nil, SignatureError
+		}
+
+		witness, err := (&typesCKB.WitnessArgs{
+			Lock: signature,
+		}).Serialize()
+		if err != nil {
+			return nil, wrapError(ServerError, err)
+		}
+		witnesses[group[0]] = witness
+	}
+	tx.Witnesses = witnesses
+
+	signed, err := fromConstructionTransaction(tx, inputCells)
+	if err != nil {
+		return nil, wrapError(ServerError, err)
+	}
+
+	return &rosetta.ConstructionCombineResponse{
+		SignedTransaction: signed,
+	}, nil
+}
+
+// ConstructionHash implements the /construction/hash endpoint.
+func (s *ConstructionAPIService) ConstructionHash(
+	ctx context.Context,
+	request *rosetta.ConstructionHashRequest,
+) (*rosetta.TransactionIdentifierResponse, *rosetta.Error) {
+	tx, err := ToTransaction(request.SignedTransaction)
+	if err != nil {
+		return nil, transactionError(err)
+	}
+
+	return &rosetta.TransactionIdentifierResponse{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: tx.Hash.String(),
+		},
 	}, nil
 }
 
@@ -37,28 +404,248 @@ func (s *ConstructionAPIService) ConstructionMetadata(
 func (s *ConstructionAPIService) ConstructionSubmit(
 	ctx context.Context,
 	request *types.ConstructionSubmitRequest,
-) (*types.ConstructionSubmitResponse, *types.Error) {
+) (*types.ConstructionSubmitResponse, *rosetta.Error) {
 	tx, err := ToTransaction(request.SignedTransaction)
 	if err != nil {
-		return nil, &types.Error{
-			Code:      4,
-			Message:   fmt.Sprintf("submit transaction error: %v", err),
-			Retriable: true,
-		}
+		return nil, transactionError(err)
 	}
+	hash := tx.Hash
 
-	hash, err := s.client.SendTransaction(ctx, tx)
-	if err != nil {
-		return nil, &types.Error{
-			Code:      4,
-			Message:   fmt.Sprintf("submit transaction error: %v", err),
-			Retriable: true,
-		}
+	if err := validateTransaction(ctx, s.cells, tx); err != nil {
+		return nil, err
 	}
 
-	return &types.ConstructionSubmitResponse{
+	response := &types.ConstructionSubmitResponse{
 		TransactionIdentifier: &types.TransactionIdentifier{
 			Hash: hash.String(),
 		},
-	}, nil
+	}
+	if s.dryRun {
+		result, err := s.client.DryRunTransaction(ctx, tx)
+		if err != nil {
+			return s.submitFailed(ctx, hash, err, response)
+		}
+		response.Metadata = map[string]interface{}{
+			"cycles": result.Cycles,
+		}
+	}
+
+	_, err = s.client.SendTransaction(ctx, tx)
+	if err != nil {
+		return s.submitFailed(ctx, hash, err, response)
+	}
+
+	return response, nil
+}
+
+// submitFailed returns response if the transaction with hash, of which the dry run or submission
+// failed with err, was already submitted, or else the classified error.
+func (s *ConstructionAPIService) submitFailed(
+	ctx context.Context,
+	hash typesCKB.Hash,
+	err error,
+	response *types.ConstructionSubmitResponse,
+) (*types.ConstructionSubmitResponse, *rosetta.Error) {
+	submitErr := submitError(err)
+	if !s.submitted(ctx, hash, submitErr) {
+		return nil, submitErr
+	}
+
+	return response, nil
+}
+
+// submitted reports whether the transaction with hash, of which the submission failed with
+// submitErr, was already submitted. A resubmitted transaction is rejected as a duplicate while it
+// is in the transaction pool and as spending dead cells once it is committed.
+func (s *ConstructionAPIService) submitted(ctx context.Context, hash typesCKB.Hash, submitErr *rosetta.Error) bool {
+	switch submitErr.Code {
+	case DuplicateTransactionError.Code:
+		return true
+	case DeadCellError.Code:
+		tx, err := s.client.GetTransaction(ctx, hash)
+		if err != nil || tx.TxStatus == nil {
+			return false
+		}
+		return tx.TxStatus.Status == typesCKB.TransactionStatusPending ||
+			tx.TxStatus.Status == typesCKB.TransactionStatusProposed ||
+			tx.TxStatus.Status == typesCKB.TransactionStatusCommitted
+	}
+
+	return false
+}
+
+// transactionError reports err, the failure to decode a transaction.
+func transactionError(err error) *rosetta.Error {
+	if errors.Is(err, errHashMismatch) {
+		return wrapError(HashMismatchError, err)
+	}
+
+	return wrapError(TransactionError, err)
+}
+
+// constructionMetadata is the metadata returned by /construction/metadata for /construction/payloads.
+type constructionMetadata struct {
+	CellDeps   []cellDep    `json:"cell_deps"`
+	InputCells []cellOutput `json:"input_cells"`
+}
+
+// constructionIntent is a transfer described by operations. Inputs are the cells spent by
+// negative operations and outputs are the cells created by positive ones.
+type constructionIntent struct {
+	inputs          []*typesCKB.OutPoint
+	inputLocks      []*typesCKB.Script
+	inputCapacities []uint64
+	outputs         []*typesCKB.CellOutput
+}
+
+func (s *ConstructionAPIService) parseIntent(operations []*types.Operation) (*constructionIntent, error) {
+	intent := &constructionIntent{}
+	for i, operation := range operations {
+		if operation.Type != "Transfer" || operation.Account == nil || operation.Amount == nil {
+			return nil, fmt.Errorf("unsupported operation %d", i)
+		}
+		if operation.Amount.Currency == nil ||
+			operation.Amount.Currency.Symbol != CkbCurrency.Symbol ||
+			operation.Amount.Currency.Decimals != CkbCurrency.Decimals {
+			return nil, fmt.Errorf("unsupported currency of operation %d", i)
+		}
+		amount, ok := new(big.Int).SetString(operation.Amount.Value, 10)
+		if !ok || amount.Sign() == 0 || !new(big.Int).Abs(amount).IsUint64() {
+			return nil, fmt.Errorf("invalid amount of operation %d", i)
+		}
+
+		addr, err := address.Parse(operation.Account.Address)
+		if err != nil {
+			return nil, err
+		}
+		if GenerateAddress(s.network, addr.Script) != operation.Account.Address {
+			return nil, fmt.Errorf("address %s is not on network %s", operation.Account.Address, s.network.Network)
+		}
+
+		if amount.Sign() > 0 {
+			intent.outputs = append(intent.outputs, &typesCKB.CellOutput{
+				Capacity: amount.Uint64(),
+				Lock:     addr.Script,
+			})
+			continue
+		}
+
+		var coinChange rosetta.CoinChange
+		if err := convertMetadata(operation.Metadata["coin_change"], &coinChange); err != nil {
+			return nil, err
+		}
+		if coinChange.CoinAction != rosetta.CoinSpent || coinChange.CoinIdentifier == nil {
+			return nil, fmt.Errorf("operation %d spends no coin", i)
+		}
+		outPoint, err := parseCoinIdentifier(coinChange.CoinIdentifier.Identifier)
+		if err != nil {
+			return nil, err
+		}
+		intent.inputs = append(intent.inputs, outPoint)
+		intent.inputLocks = append(intent.inputLocks, addr.Script)
+		intent.inputCapacities = append(intent.inputCapacities, new(big.Int).Neg(amount).Uint64())
+	}
+
+	if len(intent.inputs) == 0 || len(intent.outputs) == 0 {
+		return nil, fmt.Errorf("transfer needs both inputs and outputs")
+	}
+
+	return intent, nil
+}
+
+// checkCapacities checks the inputs of intent cover its outputs, each of which holds at least the
+// capacity it occupies. The difference is the fee.
+func checkCapacities(intent *constructionIntent) error {
+	inputs := new(big.Int)
+	for _, capacity := range intent.inputCapacities {
+		inputs.Add(inputs, new(big.Int).SetUint64(capacity))
+	}
+	outputs := new(big.Int)
+	for i, output := range intent.outputs {
+		if occupied := occupiedCapacity(output, nil); output.Capacity < occupied {
+			return fmt.Errorf("output %d holds %d shannons, occupies %d", i, output.Capacity, occupied)
+		}
+		outputs.Add(outputs, new(big.Int).SetUint64(output.Capacity))
+	}
+	if inputs.Cmp(outputs) < 0 {
+		return fmt.Errorf("inputs of %s shannons do not cover outputs of %s", inputs, outputs)
+	}
+
+	return nil
+}
+
+// signingGroups groups the indexes of inputs by lock script, in the order the locks first appear.
+// Only secp256k1 blake160 locks are supported.
+func signingGroups(inputCells []*typesCKB.CellOutput) ([][]int, error) {
+	var groups [][]int
+	var locks []*typesCKB.Script
+	for i, cell := range inputCells {
+		if !isSecp256k1Lock(cell.Lock) {
+			return nil, fmt.Errorf("unsupported lock of input %d", i)
+		}
+
+		found := false
+		for j, lock := range locks {
+			if lock.Equals(cell.Lock) {
+				groups[j] = append(groups[j], i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			locks = append(locks, cell.Lock)
+			groups = append(groups, []int{i})
+		}
+	}
+
+	return groups, nil
+}
+
+// signingMessage computes the message signed by the secp256k1 blake160 lock of a group of inputs,
+// which covers the transaction hash, the witnesses of the group and the witnesses without inputs.
+func signingMessage(tx *typesCKB.Transaction, group []int) ([]byte, error) {
+	hash, err := tx.ComputeHash()
+	if err != nil {
+		return nil, err
+	}
+
+	message := hash.Bytes()
+	appendWitness := func(witness []byte) {
+		length := make([]byte, 8)
+		binary.LittleEndian.PutUint64(length, uint64(len(witness)))
+		message = append(message, length...)
+		message = append(message, witness...)
+	}
+	for _, i := range group {
+		appendWitness(tx.Witnesses[i])
+	}
+	for i := len(tx.Inputs); i < len(tx.Witnesses); i++ {
+		appendWitness(tx.Witnesses[i])
+	}
+
+	return blake2b.Blake256(message)
+}
+
+func secp256k1Lock(args []byte) *typesCKB.Script {
+	return &typesCKB.Script{
+		CodeHash: typesCKB.HexToHash(transactionCKB.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH),
+		HashType: typesCKB.HashTypeType,
+		Args:     args,
+	}
+}
+
+func isSecp256k1Lock(script *typesCKB.Script) bool {
+	return script != nil &&
+		script.CodeHash.String() == transactionCKB.SECP256K1_BLAKE160_SIGHASH_ALL_TYPE_HASH &&
+		script.HashType == typesCKB.HashTypeType &&
+		len(script.Args) == 20
+}
+
+// convertMetadata converts loosely typed metadata decoded from json into result.
+func convertMetadata(metadata interface{}, result interface{}) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
 }
@@ -0,0 +1,855 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	"github.com/ququzone/ckb-sdk-go/crypto/secp256k1"
+	transactionCKB "github.com/ququzone/ckb-sdk-go/transaction"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+func TestConstructionSubmit(t *testing.T) {
+	signed, err := ioutil.ReadFile(filepath.Join("testdata", "signed_transaction.json"))
+	if err != nil {
+		t.Fatalf("read signed transaction: %v", err)
+	}
+
+	encoded, err := SerializeTransaction(readSignedTransaction(t))
+	if err != nil {
+		t.Fatalf("serialize signed transaction: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		fixtures    []string
+		dryRun      bool
+		transaction string
+		hash        string
+		cycles      uint64
+		err         *rosetta.Error
+	}{
+		{
+			name:        "accepted",
+			fixtures:    []string{"cell_deps", "submit"},
+			transaction: string(signed),
+			hash:        "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+		},
+		{
+			name:        "accepted in molecule encoding",
+			fixtures:    []string{"cell_deps", "submit"},
+			transaction: hexutil.Encode(encoded),
+			hash:        "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+		},
+		{
+			name:        "truncated molecule encoding",
+			transaction: hexutil.Encode(encoded[:len(encoded)-1]),
+			err:         TransactionError,
+		},
+		{
+			name:        "accepted after dry run",
+			fixtures:    []string{"cell_deps", "dry_run", "submit"},
+			dryRun:      true,
+			transaction: string(signed),
+			hash:        "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+			cycles:      810891,
+		},
+		{
+			name:        "failed dry run",
+			fixtures:    []string{"cell_deps", "dry_run_failed", "submit"},
+			dryRun:      true,
+			transaction: string(signed),
+			err:         VerificationError,
+		},
+		{
+			name:        "already in the pool",
+			fixtures:    []string{"cell_deps", "submit_rejected"},
+			transaction: string(signed),
+			hash:        "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+		},
+		{
+			name:        "already committed",
+			fixtures:    []string{"cell_deps", "submit_committed", "block"},
+			transaction: string(signed),
+			hash:        "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+		},
+		{
+			name:        "input spent by another transaction",
+			fixtures:    []string{"cell_deps", "submit_conflict"},
+			transaction: string(signed),
+			err:         DeadCellError,
+		},
+		{
+			name:        "node failure",
+			fixtures:    []string{"cell_deps", "submit_failed"},
+			transaction: string(signed),
+			err:         SubmitError,
+		},
+		{
+			name:        "malformed transaction",
+			transaction: "{",
+			err:         TransactionError,
+		},
+		{
+			name: "output without lock",
+			transaction: modifyTransaction(t, signed, func(tx map[string]interface{}) {
+				delete(tx["outputs"].([]interface{})[1].(map[string]interface{}), "lock")
+			}),
+			err: TransactionError,
+		},
+		{
+			name: "hash mismatch",
+			transaction: modifyTransaction(t, signed, func(tx map[string]interface{}) {
+				tx["hash"] = "0xb34e7a67f011999ac30943af2344bfb505007661601fd743524f87b3913f5357"
+			}),
+			err: HashMismatchError,
+		},
+		{
+			name:        "no declared hash",
+			fixtures:    []string{"cell_deps", "submit"},
+			transaction: modifyTransaction(t, signed, func(tx map[string]interface{}) {}),
+			hash:        "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+		},
+		{
+			name: "no inputs",
+			transaction: modifyTransaction(t, signed, func(tx map[string]interface{}) {
+				tx["inputs"] = []interface{}{}
+			}),
+			err: TransactionError,
+		},
+		{
+			name: "missing outputs data",
+			transaction: modifyTransaction(t, signed, func(tx map[string]interface{}) {
+				tx["outputs_data"] = tx["outputs_data"].([]interface{})[:2]
+			}),
+			err: OutputsDataError,
+		},
+		{
+			name: "missing witness",
+			transaction: modifyTransaction(t, signed, func(tx map[string]interface{}) {
+				tx["witnesses"] = []interface{}{}
+			}),
+			err: WitnessError,
+		},
+		{
+			name: "insufficient capacity",
+			transaction: modifyTransaction(t, signed, func(tx map[string]interface{}) {
+				tx["outputs"].([]interface{})[0].(map[string]interface{})["capacity"] = "0x16b969cff"
+			}),
+			err: CapacityError,
+		},
+		{
+			name:        "dead cell dep",
+			fixtures:    []string{"cell_deps_dead", "submit"},
+			transaction: string(signed),
+			err:         DeadCellError,
+		},
+		{
+			name:        "unknown cell dep",
+			fixtures:    []string{"cell_deps_unknown", "submit"},
+			transaction: string(signed),
+			err:         UnknownCellError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := newFixtureServer(t, test.fixtures...)
+			cells := NewCellStatus(dialFixtureNode(t, url))
+			service := NewConstructionAPIService(mainnet, dialFixtureClient(t, url), cells, test.dryRun)
+			response, serviceErr := service.ConstructionSubmit(context.Background(), &types.ConstructionSubmitRequest{
+				NetworkIdentifier: mainnet,
+				SignedTransaction: test.transaction,
+			})
+			assertError(t, test.err, serviceErr)
+			if test.err != nil {
+				return
+			}
+			if response.TransactionIdentifier.Hash != test.hash {
+				t.Errorf("expected hash %s, got %s", test.hash, response.TransactionIdentifier.Hash)
+			}
+			if test.dryRun && response.Metadata["cycles"] != test.cycles {
+				t.Errorf("expected %d cycles, got %v", test.cycles, response.Metadata["cycles"])
+			}
+		})
+	}
+}
+
+func TestConstructionDerive(t *testing.T) {
+	testnet := &types.NetworkIdentifier{
+		Blockchain: "CKB",
+		Network:    "Testnet",
+	}
+	// the public key the genesis block of a development chain issues cells to
+	compressed := "0x03fe6c6d09d1a0f70255cddf25c5ed57d41b5c08822ae710dc10f8c88290e0acdf"
+
+	tests := []struct {
+		name      string
+		network   *types.NetworkIdentifier
+		publicKey *rosetta.PublicKey
+		address   string
+		err       *rosetta.Error
+	}{
+		{
+			name:      "mainnet",
+			network:   mainnet,
+			publicKey: &rosetta.PublicKey{HexBytes: compressed, CurveType: rosetta.CurveTypeSecp256k1},
+			address:   "ckb1qyqvsv5240xeh85wvnau2eky8pwrhh4jr8ts6f6daz",
+		},
+		{
+			name:      "testnet",
+			network:   testnet,
+			publicKey: &rosetta.PublicKey{HexBytes: compressed, CurveType: rosetta.CurveTypeSecp256k1},
+			address:   "ckt1qyqvsv5240xeh85wvnau2eky8pwrhh4jr8ts8vyj37",
+		},
+		{
+			name:    "uncompressed",
+			network: mainnet,
+			publicKey: &rosetta.PublicKey{
+				HexBytes:  "0x04fe6c6d09d1a0f70255cddf25c5ed57d41b5c08822ae710dc10f8c88290e0acdf671d6ec922ea1f8d65a2cba4c5f58cf97db092e791e32b5ac3e3fd3ff613a583",
+				CurveType: rosetta.CurveTypeSecp256k1,
+			},
+			err: PublicKeyError,
+		},
+		{
+			name:      "not on the curve",
+			network:   mainnet,
+			publicKey: &rosetta.PublicKey{HexBytes: "0x05fe6c6d09d1a0f70255cddf25c5ed57d41b5c08822ae710dc10f8c88290e0acdf", CurveType: rosetta.CurveTypeSecp256k1},
+			err:       PublicKeyError,
+		},
+		{
+			name:      "other curve",
+			network:   mainnet,
+			publicKey: &rosetta.PublicKey{HexBytes: compressed, CurveType: "edwards25519"},
+			err:       PublicKeyError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := NewConstructionAPIService(test.network, nil, nil, false)
+			response, serviceErr := service.ConstructionDerive(context.Background(), &rosetta.ConstructionDeriveRequest{
+				NetworkIdentifier: test.network,
+				PublicKey:         test.publicKey,
+			})
+			assertError(t, test.err, serviceErr)
+			if test.err != nil {
+				return
+			}
+			if response.Address != test.address {
+				t.Errorf("expected address %s, got %s", test.address, response.Address)
+			}
+			if test.network == mainnet {
+				assertJSON(t, "construction_derive_response", response)
+			}
+		})
+	}
+}
+
+// devKey is the private key of the public key the genesis block of a development chain issues
+// cells to.
+const devKey = "d00c06bfd800d27397002dca6fb0993d5ba6399b4238b2f29ee9deb97593d2bc"
+
+// transferRequest returns the request of a transfer spending two cells of the development key,
+// paying 100 CKB to another account and the change back to the key with a fee of 1000 shannons.
+// modify changes the operations before the request is returned.
+func transferRequest(modify func(operations []*types.Operation)) *rosetta.ConstructionPayloadsRequest {
+	from := GenerateAddress(mainnet, secp256k1Lock(hexutil.MustDecode("0xc8328aabcd9b9e8e64fbc566c4385c3bdeb219d7")))
+	to := GenerateAddress(mainnet, secp256k1Lock(hexutil.MustDecode("0xe2fa82e70b062c8644b80ad7ecf6e015e5f352f6")))
+	spent := typesCKB.HexToHash("0xa332cfb34620c051cbef0cc82ac6fdbc0061b1681f091924b20134df75177f1d")
+
+	transfer := func(address string, value string, metadata map[string]interface{}) *types.Operation {
+		return &types.Operation{
+			Type:     "Transfer",
+			Account:  &types.AccountIdentifier{Address: address},
+			Amount:   &types.Amount{Value: value, Currency: CkbCurrency},
+			Metadata: metadata,
+		}
+	}
+	operations := []*types.Operation{
+		transfer(from, "-20000000000", coinChange(spent, 0, rosetta.CoinSpent)),
+		transfer(from, "-30000000000", coinChange(spent, 1, rosetta.CoinSpent)),
+		transfer(to, "10000000000", nil),
+		transfer(from, "39999999000", nil),
+	}
+	for i, operation := range operations {
+		operation.OperationIdentifier = &types.OperationIdentifier{Index: int64(i)}
+	}
+	if modify != nil {
+		modify(operations)
+	}
+
+	inputCell := func(capacity string) map[string]interface{} {
+		return map[string]interface{}{
+			"capacity": capacity,
+			"lock": map[string]interface{}{
+				"code_hash": "0x9bd7e06f3ecf4be0f2fcd2188b23f1b9fcc88e5d4b65a8637b17723bbda3cce8",
+				"hash_type": "type",
+				"args":      "0xc8328aabcd9b9e8e64fbc566c4385c3bdeb219d7",
+			},
+		}
+	}
+
+	return &rosetta.ConstructionPayloadsRequest{
+		NetworkIdentifier: mainnet,
+		Operations:        operations,
+		Metadata: map[string]interface{}{
+			"cell_deps": []interface{}{
+				map[string]interface{}{
+					"out_point": map[string]interface{}{
+						"tx_hash": "0x71a7ba8fc96349fea0ed3a5c47992e3b4084b031a42264a018e0072e8172e46c",
+						"index":   "0x0",
+					},
+					"dep_type": "dep_group",
+				},
+			},
+			"input_cells": []interface{}{inputCell("0x4a817c800"), inputCell("0x6fc23ac00")},
+		},
+	}
+}
+
+// signTransfer returns the unsigned transfer, the signature of its payload by the development key
+// and the transfer signed by the ckb-sdk, which the construction endpoints must agree with.
+func signTransfer(t *testing.T, service rosetta.ConstructionAPIServicer) (string, *rosetta.Signature, *typesCKB.Transaction) {
+	key, err := secp256k1.HexToKey(devKey)
+	if err != nil {
+		t.Fatalf("decode key: %v", err)
+	}
+	response, serviceErr := service.ConstructionPayloads(context.Background(), transferRequest(nil))
+	if serviceErr != nil {
+		t.Fatalf("payloads: %+v", serviceErr)
+	}
+	if len(response.Payloads) != 1 {
+		t.Fatalf("expected a payload, got %d", len(response.Payloads))
+	}
+
+	payload := response.Payloads[0]
+	signature, err := key.Sign(hexutil.MustDecode(payload.HexBytes))
+	if err != nil {
+		t.Fatalf("sign payload: %v", err)
+	}
+
+	expected, _, err := toConstructionTransaction(response.UnsignedTransaction)
+	if err != nil {
+		t.Fatalf("decode unsigned transaction: %v", err)
+	}
+	if err := transactionCKB.SingleSignTransaction(expected, []int{0, 1}, transactionCKB.EmptyWitnessArg, key); err != nil {
+		t.Fatalf("sign transaction: %v", err)
+	}
+
+	return response.UnsignedTransaction, &rosetta.Signature{
+		SigningPayload: payload,
+		PublicKey: &rosetta.PublicKey{
+			HexBytes:  hexutil.Encode(key.PubKey()),
+			CurveType: rosetta.CurveTypeSecp256k1,
+		},
+		SignatureType: rosetta.SignatureTypeEcdsaRecovery,
+		HexBytes:      hexutil.Encode(signature),
+	}, expected
+}
+
+func TestConstructionPreprocess(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(operations []*types.Operation)
+		inputs []string
+		err    *rosetta.Error
+	}{
+		{
+			name: "transfer",
+			inputs: []string{
+				"0xa332cfb34620c051cbef0cc82ac6fdbc0061b1681f091924b20134df75177f1d:0",
+				"0xa332cfb34620c051cbef0cc82ac6fdbc0061b1681f091924b20134df75177f1d:1",
+			},
+		},
+		{
+			name: "other currency",
+			modify: func(operations []*types.Operation) {
+				operations[2].Amount.Currency = &types.Currency{Symbol: "TST", Decimals: 8}
+			},
+			err: OperationError,
+		},
+		{
+			name: "address of another network",
+			modify: func(operations []*types.Operation) {
+				operations[2].Account.Address = "ckt1qyqvsv5240xeh85wvnau2eky8pwrhh4jr8ts8vyj37"
+			},
+			err: OperationError,
+		},
+		{
+			name: "no outputs",
+			modify: func(operations []*types.Operation) {
+				operations[2].Amount.Value = "-10000000000"
+				operations[3].Amount.Value = "-39999999000"
+			},
+			err: OperationError,
+		},
+	}
+
+	service := NewConstructionAPIService(mainnet, nil, nil, false)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := transferRequest(test.modify)
+			response, serviceErr := service.ConstructionPreprocess(context.Background(), &rosetta.ConstructionPreprocessRequest{
+				NetworkIdentifier: mainnet,
+				Operations:        request.Operations,
+			})
+			assertError(t, test.err, serviceErr)
+			if test.err != nil {
+				return
+			}
+			if !reflect.DeepEqual(response.Options["inputs"], test.inputs) {
+				t.Errorf("expected inputs %v, got %v", test.inputs, response.Options["inputs"])
+			}
+		})
+	}
+}
+
+func TestConstructionMetadata(t *testing.T) {
+	spent := "0xa332cfb34620c051cbef0cc82ac6fdbc0061b1681f091924b20134df75177f1d"
+
+	tests := []struct {
+		name   string
+		inputs []string
+		err    *rosetta.Error
+	}{
+		{
+			name:   "live inputs",
+			inputs: []string{spent + ":0", spent + ":1"},
+		},
+		{
+			name:   "spent input",
+			inputs: []string{spent + ":0", spent + ":2"},
+			err:    OperationError,
+		},
+		{
+			name:   "unknown input",
+			inputs: []string{spent + ":3"},
+			err:    OperationError,
+		},
+		{
+			name:   "malformed coin",
+			inputs: []string{spent},
+			err:    OperationError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := newFixtureServer(t, "construction_metadata")
+			service := NewConstructionAPIService(mainnet, dialFixtureClient(t, url), NewCellStatus(dialFixtureNode(t, url)), false)
+			response, serviceErr := service.ConstructionMetadata(context.Background(), &types.ConstructionMetadataRequest{
+				NetworkIdentifier: mainnet,
+				Options: map[string]interface{}{
+					"inputs": test.inputs,
+				},
+			})
+			assertError(t, test.err, serviceErr)
+			if test.err != nil {
+				return
+			}
+			assertJSON(t, "construction_metadata_response", response)
+
+			// the metadata builds the transfer the payloads of the transfer request build
+			request := transferRequest(nil)
+			expected, serviceErr := service.ConstructionPayloads(context.Background(), request)
+			if serviceErr != nil {
+				t.Fatalf("payloads: %+v", serviceErr)
+			}
+			request.Metadata = response.Metadata
+			payloads, serviceErr := service.ConstructionPayloads(context.Background(), request)
+			if serviceErr != nil {
+				t.Fatalf("payloads of the metadata: %+v", serviceErr)
+			}
+			if payloads.UnsignedTransaction != expected.UnsignedTransaction {
+				t.Errorf("expected transaction %s, got %s", expected.UnsignedTransaction, payloads.UnsignedTransaction)
+			}
+		})
+	}
+}
+
+func TestConstructionPayloads(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(operations []*types.Operation)
+		err    *rosetta.Error
+	}{
+		{
+			name: "transfer",
+		},
+		{
+			name: "outputs exceed inputs",
+			modify: func(operations []*types.Operation) {
+				operations[3].Amount.Value = "40000001000"
+			},
+			err: OperationError,
+		},
+		{
+			name: "output below its occupied capacity",
+			modify: func(operations []*types.Operation) {
+				operations[2].Amount.Value = "6000000000"
+			},
+			err: OperationError,
+		},
+		{
+			name: "spent capacity differs from the input cell",
+			modify: func(operations []*types.Operation) {
+				operations[0].Amount.Value = "-20000001000"
+			},
+			err: OperationError,
+		},
+	}
+
+	service := NewConstructionAPIService(mainnet, nil, nil, false)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, serviceErr := service.ConstructionPayloads(context.Background(), transferRequest(test.modify))
+			assertError(t, test.err, serviceErr)
+			if test.err != nil {
+				return
+			}
+			assertJSON(t, "construction_payloads_response", response)
+		})
+	}
+
+	// the secp256k1 signature is deterministic, so the ckb-sdk signed the payload if it made the
+	// same signature
+	unsigned, signature, expected := signTransfer(t, service)
+	args, err := DeserializeWitnessArgs(expected.Witnesses[0])
+	if err != nil {
+		t.Fatalf("decode signed witness: %v", err)
+	}
+	if hexutil.Encode(args.Lock) != signature.HexBytes {
+		t.Errorf("expected signature %s of the ckb-sdk, got %s", hexutil.Encode(args.Lock), signature.HexBytes)
+	}
+
+	// the placeholder of the signature is in the witness of the first input of the group
+	tx, _, err := toConstructionTransaction(unsigned)
+	if err != nil {
+		t.Fatalf("decode unsigned transaction: %v", err)
+	}
+	placeholder, err := transactionCKB.EmptyWitnessArg.Serialize()
+	if err != nil {
+		t.Fatalf("serialize placeholder: %v", err)
+	}
+	if !reflect.DeepEqual(tx.Witnesses, [][]byte{placeholder, {}}) {
+		t.Errorf("unexpected witnesses %x", tx.Witnesses)
+	}
+}
+
+func TestConstructionParse(t *testing.T) {
+	service := NewConstructionAPIService(mainnet, nil, nil, false)
+	unsigned, signature, _ := signTransfer(t, service)
+	combined, serviceErr := service.ConstructionCombine(context.Background(), &rosetta.ConstructionCombineRequest{
+		NetworkIdentifier:   mainnet,
+		UnsignedTransaction: unsigned,
+		Signatures:          []*rosetta.Signature{signature},
+	})
+	if serviceErr != nil {
+		t.Fatalf("combine: %+v", serviceErr)
+	}
+	signer := GenerateAddress(mainnet, secp256k1Lock(hexutil.MustDecode("0xc8328aabcd9b9e8e64fbc566c4385c3bdeb219d7")))
+
+	tests := []struct {
+		name        string
+		signed      bool
+		transaction string
+		signers     []string
+		err         *rosetta.Error
+	}{
+		{
+			name:        "unsigned",
+			transaction: unsigned,
+			signers:     []string{},
+		},
+		{
+			name:        "signed",
+			signed:      true,
+			transaction: combined.SignedTransaction,
+			signers:     []string{signer},
+		},
+		{
+			name: "missing input cell",
+			transaction: modifyConstruction(t, unsigned, func(tx map[string]interface{}) {
+				tx["input_cells"] = tx["input_cells"].([]interface{})[:1]
+			}),
+			err: TransactionError,
+		},
+		{
+			name:        "malformed transaction",
+			transaction: "{",
+			err:         TransactionError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, serviceErr := service.ConstructionParse(context.Background(), &rosetta.ConstructionParseRequest{
+				NetworkIdentifier: mainnet,
+				Signed:            test.signed,
+				Transaction:       test.transaction,
+			})
+			assertError(t, test.err, serviceErr)
+			if test.err != nil {
+				return
+			}
+			if operations := transferRequest(nil).Operations; !reflect.DeepEqual(response.Operations, operations) {
+				t.Errorf("expected the operations of the transfer, got %+v", response.Operations)
+			}
+			if !reflect.DeepEqual(response.Signers, test.signers) {
+				t.Errorf("expected signers %v, got %v", test.signers, response.Signers)
+			}
+		})
+	}
+}
+
+func TestConstructionCombine(t *testing.T) {
+	service := NewConstructionAPIService(mainnet, nil, nil, false)
+	unsigned, signature, expected := signTransfer(t, service)
+
+	other, err := secp256k1.HexToKey("e79f3207ea4980b7fed79956d5934249ceac4751a4fae01a0f7c4a96884bc4e3")
+	if err != nil {
+		t.Fatalf("decode key: %v", err)
+	}
+	forged, err := other.Sign(hexutil.MustDecode(signature.SigningPayload.HexBytes))
+	if err != nil {
+		t.Fatalf("sign payload: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		transaction string
+		signatures  []*rosetta.Signature
+		err         *rosetta.Error
+	}{
+		{
+			name:        "signed",
+			transaction: unsigned,
+			signatures:  []*rosetta.Signature{nil, signature},
+		},
+		{
+			name:        "no signature",
+			transaction: unsigned,
+			signatures:  []*rosetta.Signature{nil},
+			err:         SignatureError,
+		},
+		{
+			name:        "signature of another key",
+			transaction: unsigned,
+			signatures: []*rosetta.Signature{{
+				SigningPayload: signature.SigningPayload,
+				SignatureType:  rosetta.SignatureTypeEcdsaRecovery,
+				HexBytes:       hexutil.Encode(forged),
+			}},
+			err: SignatureError,
+		},
+		{
+			name: "missing witness",
+			transaction: modifyConstruction(t, unsigned, func(tx map[string]interface{}) {
+				transaction := tx["transaction"].(map[string]interface{})
+				transaction["witnesses"] = transaction["witnesses"].([]interface{})[:1]
+			}),
+			signatures: []*rosetta.Signature{signature},
+			err:        TransactionError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, serviceErr := service.ConstructionCombine(context.Background(), &rosetta.ConstructionCombineRequest{
+				NetworkIdentifier:   mainnet,
+				UnsignedTransaction: test.transaction,
+				Signatures:          test.signatures,
+			})
+			assertError(t, test.err, serviceErr)
+			if test.err != nil {
+				return
+			}
+			tx, _, err := toConstructionTransaction(response.SignedTransaction)
+			if err != nil {
+				t.Fatalf("decode signed transaction: %v", err)
+			}
+			if tx.Hash != expected.Hash {
+				t.Errorf("expected hash %s, got %s", expected.Hash.String(), tx.Hash.String())
+			}
+			if !reflect.DeepEqual(tx.Witnesses, expected.Witnesses) {
+				t.Errorf("expected witnesses %x of the ckb-sdk, got %x", expected.Witnesses, tx.Witnesses)
+			}
+		})
+	}
+}
+
+// modifyConstruction returns the construction transaction data changed by modify.
+func modifyConstruction(t *testing.T, data string, modify func(tx map[string]interface{})) string {
+	var tx map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &tx); err != nil {
+		t.Fatalf("decode transaction: %v", err)
+	}
+	modify(tx)
+	result, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("encode transaction: %v", err)
+	}
+	return string(result)
+}
+
+func TestConstructionHash(t *testing.T) {
+	signed, err := ioutil.ReadFile(filepath.Join("testdata", "signed_transaction.json"))
+	if err != nil {
+		t.Fatalf("read signed transaction: %v", err)
+	}
+	encoded, err := SerializeTransaction(readSignedTransaction(t))
+	if err != nil {
+		t.Fatalf("serialize signed transaction: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		transaction string
+		hash        string
+		err         *rosetta.Error
+	}{
+		{
+			name:        "json",
+			transaction: string(signed),
+			hash:        "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+		},
+		{
+			name:        "molecule",
+			transaction: hexutil.Encode(encoded),
+			hash:        "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+		},
+		{
+			name: "unsigned",
+			transaction: modifyTransaction(t, signed, func(tx map[string]interface{}) {
+				tx["witnesses"] = []interface{}{"0x"}
+			}),
+			hash: "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+		},
+		{
+			name: "hash mismatch",
+			transaction: modifyTransaction(t, signed, func(tx map[string]interface{}) {
+				tx["hash"] = "0xb34e7a67f011999ac30943af2344bfb505007661601fd743524f87b3913f5357"
+			}),
+			err: HashMismatchError,
+		},
+		{
+			name:        "malformed transaction",
+			transaction: "{",
+			err:         TransactionError,
+		},
+	}
+
+	service := NewConstructionAPIService(mainnet, nil, nil, false)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, serviceErr := service.ConstructionHash(context.Background(), &rosetta.ConstructionHashRequest{
+				NetworkIdentifier: mainnet,
+				SignedTransaction: test.transaction,
+			})
+			assertError(t, test.err, serviceErr)
+			if test.err != nil {
+				return
+			}
+			if response.TransactionIdentifier.Hash != test.hash {
+				t.Errorf("expected hash %s, got %s", test.hash, response.TransactionIdentifier.Hash)
+			}
+		})
+	}
+}
+
+// modifyTransaction returns the JSON transaction data changed by modify, without the hash it
+// declares unless modify sets one.
+func modifyTransaction(t *testing.T, data []byte, modify func(tx map[string]interface{})) string {
+	var tx map[string]interface{}
+	if err := json.Unmarshal(data, &tx); err != nil {
+		t.Fatalf("decode transaction: %v", err)
+	}
+	delete(tx, "hash")
+	modify(tx)
+	result, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("encode transaction: %v", err)
+	}
+	return string(result)
+}
+
+// nodeError is a json-rpc error returned by the node.
+type nodeError struct {
+	code    int
+	message string
+}
+
+func (e *nodeError) Error() string {
+	return e.message
+}
+
+func (e *nodeError) ErrorCode() int {
+	return e.code
+}
+
+func TestSubmitError(t *testing.T) {
+	tests := []struct {
+		name  string
+		cause error
+		err   *rosetta.Error
+	}{
+		{
+			name:  "dead input",
+			cause: &nodeError{-301, "TransactionFailedToResolve: Resolve failed Dead(OutPoint(0xa332cfb34620c051cbef0cc82ac6fdbc0061b1681f091924b20134df75177f1d01000000))"},
+			err:   DeadCellError,
+		},
+		{
+			name:  "unknown input",
+			cause: &nodeError{-301, "TransactionFailedToResolve: Resolve failed Unknown(OutPoint(0xa332cfb34620c051cbef0cc82ac6fdbc0061b1681f091924b20134df75177f1d01000000))"},
+			err:   UnknownCellError,
+		},
+		{
+			name:  "script failure",
+			cause: &nodeError{-302, "TransactionFailedToVerify: Verification failed Script(ValidationFailure(-31))"},
+			err:   VerificationError,
+		},
+		{
+			name:  "low fee rate",
+			cause: &nodeError{-1104, "PoolRejectedTransactionByMinFeeRate: The min fee rate is 1000 shannons/KB, so the transaction fee should be 464 shannons at least, but only got 100"},
+			err:   FeeRateError,
+		},
+		{
+			name:  "ancestors limit",
+			cause: &nodeError{-1105, "PoolRejectedTransactionByMaxAncestorsCountLimit: Transaction exceeded maximum ancestors count limit, try send it later"},
+			err:   AncestorsLimitError,
+		},
+		{
+			name:  "pool full",
+			cause: &nodeError{-1106, "PoolIsFull: Transaction pool exceeded maximum size or cycles limit"},
+			err:   PoolFullError,
+		},
+		{
+			name:  "non-standard",
+			cause: &nodeError{-1102, "PoolRejectedTransactionByOutputsValidator: The transaction is rejected by OutputsValidator set in params[1]: well_known_scripts_only"},
+			err:   TransactionRejectedError,
+		},
+		{
+			name:  "internal error",
+			cause: &nodeError{-1, "CKBInternalError: database error"},
+			err:   SubmitError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := submitError(test.cause)
+			assertError(t, test.err, err)
+			if err.Details["error"] != test.cause.Error() {
+				t.Errorf("expected the node message in the details, got %v", err.Details)
+			}
+		})
+	}
+}
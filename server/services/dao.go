@@ -0,0 +1,26 @@
+package services
+
+import (
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+// daoTypeHash is the code hash of the Nervos DAO type script, which is the same on Mainnet and Testnet.
+var daoTypeHash = typesCKB.HexToHash("0x82d76d1b75fe2fd9a27dfbaa65a039221a380d76c926f378d3f81cf3e7e13f2e")
+
+func isDaoScript(script *typesCKB.Script) bool {
+	return script != nil && script.CodeHash == daoTypeHash && script.HashType == typesCKB.HashTypeType
+}
+
+// isDaoDeposit reports whether the data of a DAO cell marks a deposit. Deposit cells carry 8 zero
+// bytes while prepared cells carry the deposit block number.
+func isDaoDeposit(data []byte) bool {
+	if len(data) != 8 {
+		return false
+	}
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+)
+
+// registry holds every error returned by the services, in the order of their codes, which is the
+// list advertised by /network/options. Codes are stable and never reused.
+var registry []*rosetta.Error
+
+var (
+	NoImplementError = register(&rosetta.Error{
+		Code:        1,
+		Message:     "not implemented",
+		Description: "The endpoint is not supported on this network.",
+		Retriable:   false,
+	})
+
+	RpcError = register(&rosetta.Error{
+		Code:        2,
+		Message:     "rpc error",
+		Description: "A call to the CKB node or its indexer failed, the details hold the node error.",
+		Retriable:   true,
+	})
+
+	AddressError = register(&rosetta.Error{
+		Code:        3,
+		Message:     "address error",
+		Description: "The address is not a valid CKB address.",
+		Retriable:   false,
+	})
+
+	SubmitError = register(&rosetta.Error{
+		Code:        4,
+		Message:     "submit transaction error",
+		Description: "The transaction could not be submitted to the node for a reason not covered by a more specific error, the details hold the node error.",
+		Retriable:   true,
+	})
+
+	ServerError = register(&rosetta.Error{
+		Code:        5,
+		Message:     "server error",
+		Description: "The data returned by the node is inconsistent.",
+		Retriable:   true,
+	})
+
+	PublicKeyError = register(&rosetta.Error{
+		Code:        6,
+		Message:     "public key error",
+		Description: "The public key is not a valid secp256k1 public key.",
+		Retriable:   false,
+	})
+
+	OperationError = register(&rosetta.Error{
+		Code:        7,
+		Message:     "operation error",
+		Description: "The operations do not describe a transaction which can be constructed.",
+		Retriable:   false,
+	})
+
+	TransactionError = register(&rosetta.Error{
+		Code:        8,
+		Message:     "transaction error",
+		Description: "The transaction cannot be decoded or is not a valid CKB transaction.",
+		Retriable:   false,
+	})
+
+	SignatureError = register(&rosetta.Error{
+		Code:        9,
+		Message:     "signature error",
+		Description: "A signature is missing, malformed or does not match its signing payload.",
+		Retriable:   false,
+	})
+
+	MempoolTransactionError = register(&rosetta.Error{
+		Code:        10,
+		Message:     "transaction not in mempool",
+		Description: "The transaction is neither pending nor proposed in the transaction pool.",
+		Retriable:   false,
+	})
+
+	NetworkError = register(&rosetta.Error{
+		Code:        11,
+		Message:     "network not supported",
+		Description: "The network identifier does not name a network served.",
+		Retriable:   false,
+	})
+
+	TimeoutError = register(&rosetta.Error{
+		Code:        12,
+		Message:     "request timeout",
+		Description: "The request was not served within the timeout configured for the endpoint.",
+		Retriable:   true,
+	})
+
+	SearchError = register(&rosetta.Error{
+		Code:        13,
+		Message:     "invalid search",
+		Description: "The limit, block range or cursor of the search is invalid.",
+		Retriable:   false,
+	})
+
+	EventsError = register(&rosetta.Error{
+		Code:        14,
+		Message:     "invalid events range",
+		Description: "The offset or limit of the events requested is invalid.",
+		Retriable:   false,
+	})
+
+	BlockNotFoundError = register(&rosetta.Error{
+		Code:        15,
+		Message:     "block not found or orphaned",
+		Description: "The block is unknown to the node, not on the canonical chain or does not match both the hash and index requested.",
+		Retriable:   true,
+	})
+
+	BlockIdentifierError = register(&rosetta.Error{
+		Code:        16,
+		Message:     "invalid block identifier",
+		Description: "The block index is negative or the block hash is not a 32 bytes hex string.",
+		Retriable:   false,
+	})
+
+	InvalidRequestError = register(rosetta.InvalidRequestError)
+
+	DuplicateTransactionError = register(&rosetta.Error{
+		Code:        18,
+		Message:     "transaction already submitted",
+		Description: "The transaction is already in the transaction pool of the node.",
+		Retriable:   false,
+	})
+
+	DeadCellError = register(&rosetta.Error{
+		Code:        19,
+		Message:     "cell already spent",
+		Description: "An input or dependency of the transaction is a cell which has been spent, the transaction can never be committed.",
+		Retriable:   false,
+	})
+
+	UnknownCellError = register(&rosetta.Error{
+		Code:        20,
+		Message:     "unknown cell",
+		Description: "An input or dependency of the transaction is a cell unknown to the node, which may be created by a transaction not yet received.",
+		Retriable:   true,
+	})
+
+	VerificationError = register(&rosetta.Error{
+		Code:        21,
+		Message:     "transaction verification failed",
+		Description: "A script of the transaction failed or the transaction breaks a consensus rule.",
+		Retriable:   false,
+	})
+
+	FeeRateError = register(&rosetta.Error{
+		Code:        22,
+		Message:     "fee rate too low",
+		Description: "The fee rate of the transaction is below the minimum fee rate of the transaction pool.",
+		Retriable:   false,
+	})
+
+	PoolFullError = register(&rosetta.Error{
+		Code:        23,
+		Message:     "transaction pool full",
+		Description: "The transaction pool of the node has no room for the transaction.",
+		Retriable:   true,
+	})
+
+	AncestorsLimitError = register(&rosetta.Error{
+		Code:        24,
+		Message:     "too many pending ancestors",
+		Description: "The transaction depends on more transactions of the pool than the pool allows, until they are committed.",
+		Retriable:   true,
+	})
+
+	TransactionRejectedError = register(&rosetta.Error{
+		Code:        25,
+		Message:     "transaction rejected",
+		Description: "The transaction pool rejected the transaction as malformed or non-standard.",
+		Retriable:   false,
+	})
+
+	OutputsDataError = register(&rosetta.Error{
+		Code:        26,
+		Message:     "outputs data mismatch",
+		Description: "The transaction does not have exactly one outputs data entry for each output.",
+		Retriable:   false,
+	})
+
+	CapacityError = register(&rosetta.Error{
+		Code:        27,
+		Message:     "insufficient cell capacity",
+		Description: "An output has less capacity than the bytes it occupies, which are its capacity, scripts and data at one CKByte per byte.",
+		Retriable:   false,
+	})
+
+	WitnessError = register(&rosetta.Error{
+		Code:        28,
+		Message:     "missing witness",
+		Description: "The transaction has fewer witnesses than inputs.",
+		Retriable:   false,
+	})
+
+	HashMismatchError = register(&rosetta.Error{
+		Code:        29,
+		Message:     "transaction hash mismatch",
+		Description: "The hash declared by the transaction is not the hash computed from its contents.",
+		Retriable:   false,
+	})
+
+	TransactionNotFoundError = register(&rosetta.Error{
+		Code:        30,
+		Message:     "transaction not found",
+		Description: "The transaction is unknown to the node.",
+		Retriable:   true,
+	})
+
+	PseudoAccountError = register(&rosetta.Error{
+		Code:        31,
+		Message:     "pseudo account",
+		Description: "The fee and dao pseudo accounts hold no cells, they have no balance or coins and are exempt from reconciliation.",
+		Retriable:   false,
+	})
+)
+
+// register adds err to the registry.
+func register(err *rosetta.Error) *rosetta.Error {
+	registry = append(registry, err)
+
+	return err
+}
+
+// Errors returns the registered errors.
+func Errors() []*rosetta.Error {
+	return registry
+}
+
+// wrapError returns err with its details describing cause.
+func wrapError(err *rosetta.Error, cause error) *rosetta.Error {
+	wrapped := *err
+	wrapped.Details = map[string]interface{}{
+		"error": cause.Error(),
+	}
+
+	return &wrapped
+}
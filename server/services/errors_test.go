@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+)
+
+// assertError fails t unless actual is expected, with or without details, and is listed by
+// /network/options.
+func assertError(t *testing.T, expected *rosetta.Error, actual *rosetta.Error) {
+	t.Helper()
+	if expected == nil || actual == nil {
+		if expected != actual {
+			t.Fatalf("expected error %v, got %v", expected, actual)
+		}
+		return
+	}
+	if actual.Code != expected.Code || actual.Message != expected.Message || actual.Retriable != expected.Retriable {
+		t.Fatalf("expected error %v, got %v", expected, actual)
+	}
+	if !registered(actual) {
+		t.Fatalf("error %v is not listed by /network/options", actual)
+	}
+}
+
+func registered(err *rosetta.Error) bool {
+	for _, e := range Errors() {
+		if e.Code == err.Code && e.Message == err.Message && e.Retriable == err.Retriable {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestNetworkOptionsErrors(t *testing.T) {
+	service := NewNetworkAPIService(mainnet, newFixtureClient(t, "network"), nil, 0)
+	response, err := service.NetworkOptions(context.Background(), &types.NetworkRequest{
+		NetworkIdentifier: mainnet,
+	})
+	assertError(t, nil, err)
+
+	codes := map[int32]bool{}
+	for _, e := range response.Allow.Errors {
+		if codes[e.Code] {
+			t.Errorf("error code %d is listed twice", e.Code)
+		}
+		codes[e.Code] = true
+		if e.Message == "" || e.Description == "" {
+			t.Errorf("error %d has no message or description", e.Code)
+		}
+		if e.Details != nil {
+			t.Errorf("error %d is listed with details", e.Code)
+		}
+	}
+	for _, e := range Errors() {
+		if !codes[e.Code] {
+			t.Errorf("registered error %d is not listed", e.Code)
+		}
+	}
+}
+
+// TestErrorsRegistered fails when an error is built outside of the registry, as such an error
+// would not be listed by /network/options. Errors returned with details are copies of registered
+// errors made by wrapError.
+func TestErrorsRegistered(t *testing.T) {
+	for _, dir := range []string{".", filepath.Join("..", "rosetta")} {
+		files := token.NewFileSet()
+		packages, err := parser.ParseDir(files, dir, func(info os.FileInfo) bool {
+			return !strings.HasSuffix(info.Name(), "_test.go")
+		}, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", dir, err)
+		}
+
+		for _, pkg := range packages {
+			for _, file := range pkg.Files {
+				allowed := map[ast.Expr]bool{}
+				ast.Inspect(file, func(node ast.Node) bool {
+					switch node := node.(type) {
+					case *ast.CallExpr:
+						if fun, ok := node.Fun.(*ast.Ident); ok && fun.Name == "register" {
+							for _, arg := range node.Args {
+								allowed[arg] = true
+							}
+						}
+					case *ast.ValueSpec:
+						// the request error of the controllers, registered by the services
+						for i, name := range node.Names {
+							if name.Name == "InvalidRequestError" && i < len(node.Values) {
+								allowed[node.Values[i]] = true
+							}
+						}
+					case *ast.UnaryExpr:
+						if allowed[node] {
+							allowed[node.X] = true
+						}
+					case *ast.CompositeLit:
+						if isErrorType(node.Type) && !allowed[node] {
+							t.Errorf("%s: error built outside of the registry", files.Position(node.Pos()))
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+}
+
+func isErrorType(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name == "Error"
+	case *ast.SelectorExpr:
+		return expr.Sel.Name == "Error"
+	}
+
+	return false
+}
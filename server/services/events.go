@@ -0,0 +1,304 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+const (
+	defaultEventsLimit = 100
+	maxEventsLimit     = 1000
+
+	// retainedBlocks is the number of recent canonical blocks a BlockWatcher checks for a
+	// reorganization, which is far deeper than any reorganization expected on CKB.
+	retainedBlocks = 1000
+)
+
+// BlockEvents is the log of the block events observed on a network, persisted as one json event
+// per line so that the sequence numbers survive a restart.
+type BlockEvents struct {
+	lock   sync.RWMutex
+	file   *os.File
+	events []*rosetta.BlockEvent
+}
+
+// OpenBlockEvents opens the event log at path, creating it if it does not exist. An incomplete last
+// event, left by a crash while it was written, is discarded.
+func OpenBlockEvents(path string) (*BlockEvents, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*rosetta.BlockEvent
+	var offset int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// a line without its newline was not completely written
+			break
+		}
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		var event rosetta.BlockEvent
+		if err = json.Unmarshal(bytes.TrimSpace(line), &event); err != nil || event.Sequence != int64(len(events)) {
+			file.Close()
+			return nil, fmt.Errorf("invalid event %d in %s", len(events), path)
+		}
+		events = append(events, &event)
+		offset += int64(len(line))
+	}
+	if err = file.Truncate(offset); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &BlockEvents{
+		file:   file,
+		events: events,
+	}, nil
+}
+
+// Close closes the underlying file.
+func (e *BlockEvents) Close() error {
+	return e.file.Close()
+}
+
+// Range returns the sequence number of the latest event and at most limit events starting at
+// sequence number offset.
+func (e *BlockEvents) Range(offset int64, limit int64) (int64, []*rosetta.BlockEvent) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	result := []*rosetta.BlockEvent{}
+	for i := offset; i < int64(len(e.events)) && int64(len(result)) < limit; i++ {
+		result = append(result, e.events[i])
+	}
+
+	return int64(len(e.events)) - 1, result
+}
+
+// add persists a new event of eventType for block.
+func (e *BlockEvents) add(eventType string, block *types.BlockIdentifier) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	event := &rosetta.BlockEvent{
+		Sequence:        int64(len(e.events)),
+		BlockIdentifier: block,
+		Type:            eventType,
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err = e.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err = e.file.Sync(); err != nil {
+		return err
+	}
+	e.events = append(e.events, event)
+
+	return nil
+}
+
+// chain replays the events to the most recent canonical blocks, oldest first.
+func (e *BlockEvents) chain() []*types.BlockIdentifier {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	var chain []*types.BlockIdentifier
+	for _, event := range e.events {
+		if event.Type == rosetta.BlockRemoved {
+			if len(chain) > 0 {
+				chain = chain[:len(chain)-1]
+			}
+			continue
+		}
+		chain = append(chain, event.BlockIdentifier)
+	}
+	if len(chain) > retainedBlocks {
+		chain = chain[len(chain)-retainedBlocks:]
+	}
+
+	return chain
+}
+
+// BlockWatcher follows the tip of a node and records the blocks added to and removed from the
+// canonical chain in BlockEvents. The first block recorded is the tip when the log is empty.
+type BlockWatcher struct {
+	client ChainClient
+	events *BlockEvents
+	chain  []*types.BlockIdentifier
+}
+
+// NewBlockWatcher creates a new instance of a BlockWatcher resuming from the chain recorded in events.
+func NewBlockWatcher(client ChainClient, events *BlockEvents) *BlockWatcher {
+	return &BlockWatcher{
+		client: client,
+		events: events,
+		chain:  events.chain(),
+	}
+}
+
+// Run syncs the events with the node every interval until ctx is done.
+func (w *BlockWatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.sync(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("sync block events error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync records the events turning the recorded chain into the canonical chain of the node.
+func (w *BlockWatcher) sync(ctx context.Context) error {
+	tip, err := w.client.GetTipHeader(ctx)
+	if err != nil {
+		return err
+	}
+	if len(w.chain) == 0 {
+		return w.add(tip)
+	}
+
+	for {
+		head := w.chain[len(w.chain)-1]
+		canonical := head.Index <= int64(tip.Number)
+		if canonical {
+			header, err := w.client.GetHeaderByNumber(ctx, uint64(head.Index))
+			if err != nil {
+				return err
+			}
+			canonical = header.Hash.String() == head.Hash
+		}
+
+		if !canonical {
+			if err = w.events.add(rosetta.BlockRemoved, head); err != nil {
+				return err
+			}
+			w.chain = w.chain[:len(w.chain)-1]
+			if len(w.chain) > 0 {
+				continue
+			}
+
+			// the reorganization is deeper than the blocks retained, restart from the fork
+			number := uint64(head.Index)
+			if number > tip.Number {
+				number = tip.Number
+			}
+			header, err := w.client.GetHeaderByNumber(ctx, number)
+			if err != nil {
+				return err
+			}
+			if err = w.add(header); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if head.Index >= int64(tip.Number) {
+			return nil
+		}
+		next, err := w.client.GetHeaderByNumber(ctx, uint64(head.Index)+1)
+		if err != nil {
+			return err
+		}
+		if next.ParentHash.String() != head.Hash {
+			// the chain is being reorganized, retry at the next sync
+			return nil
+		}
+		if err = w.add(next); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *BlockWatcher) add(header *typesCKB.Header) error {
+	block := &types.BlockIdentifier{
+		Index: int64(header.Number),
+		Hash:  header.Hash.String(),
+	}
+	if err := w.events.add(rosetta.BlockAdded, block); err != nil {
+		return err
+	}
+
+	w.chain = append(w.chain, block)
+	if len(w.chain) > retainedBlocks {
+		w.chain = w.chain[len(w.chain)-retainedBlocks:]
+	}
+
+	return nil
+}
+
+// EventsAPIService implements the rosetta.EventsAPIServicer interface.
+type EventsAPIService struct {
+	network *types.NetworkIdentifier
+	events  *BlockEvents
+}
+
+// NewEventsAPIService creates a new instance of an EventsAPIService. Events are nil when the
+// network does not record block events.
+func NewEventsAPIService(network *types.NetworkIdentifier, events *BlockEvents) rosetta.EventsAPIServicer {
+	return &EventsAPIService{
+		network: network,
+		events:  events,
+	}
+}
+
+// EventsBlocks implements the /events/blocks endpoint.
+func (s *EventsAPIService) EventsBlocks(
+	ctx context.Context,
+	request *rosetta.EventsBlocksRequest,
+) (*rosetta.EventsBlocksResponse, *rosetta.Error) {
+	if s.events == nil {
+		return nil, NoImplementError
+	}
+
+	offset := int64(0)
+	if request.Offset != nil {
+		offset = *request.Offset
+	}
+	limit := int64(defaultEventsLimit)
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if offset < 0 || limit <= 0 || limit > maxEventsLimit {
+		return nil, EventsError
+	}
+
+	maxSequence, events := s.events.Range(offset, limit)
+
+	return &rosetta.EventsBlocksResponse{
+		MaxSequence: maxSequence,
+		Events:      events,
+	}, nil
+}
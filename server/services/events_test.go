@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+// forkClient serves the headers of a chain which tests extend and reorganize.
+type forkClient struct {
+	ChainClient
+	headers []*typesCKB.Header
+}
+
+func (c *forkClient) GetTipHeader(ctx context.Context) (*typesCKB.Header, error) {
+	return c.headers[len(c.headers)-1], nil
+}
+
+func (c *forkClient) GetHeaderByNumber(ctx context.Context, number uint64) (*typesCKB.Header, error) {
+	return c.headers[number], nil
+}
+
+// extend appends count blocks to the chain after dropping the blocks above fork, tagging the new
+// block hashes with branch.
+func (c *forkClient) extend(fork uint64, count int, branch byte) {
+	c.headers = c.headers[:fork+1]
+	for i := 0; i < count; i++ {
+		parent := c.headers[len(c.headers)-1]
+		number := parent.Number + 1
+		c.headers = append(c.headers, &typesCKB.Header{
+			Number:     number,
+			Hash:       typesCKB.BytesToHash([]byte{branch, byte(number)}),
+			ParentHash: parent.Hash,
+		})
+	}
+}
+
+func TestBlockWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.log")
+
+	client := &forkClient{
+		headers: []*typesCKB.Header{{Hash: typesCKB.BytesToHash([]byte{0})}},
+	}
+	client.extend(0, 3, 'a')
+
+	events, err := OpenBlockEvents(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher := NewBlockWatcher(client, events)
+	steps := []struct {
+		fork   uint64
+		count  int
+		branch byte
+	}{
+		{fork: 3, count: 2, branch: 'a'},
+		{fork: 3, count: 3, branch: 'b'},
+	}
+	if err = watcher.sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range steps {
+		client.extend(step.fork, step.count, step.branch)
+		if err = watcher.sync(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	events.Close()
+
+	// the log is resumed after a restart
+	events, err = OpenBlockEvents(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer events.Close()
+	client.extend(6, 1, 'b')
+	if err = NewBlockWatcher(client, events).sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		eventType string
+		index     int64
+		branch    byte
+	}{
+		{rosetta.BlockAdded, 3, 'a'},
+		{rosetta.BlockAdded, 4, 'a'},
+		{rosetta.BlockAdded, 5, 'a'},
+		{rosetta.BlockRemoved, 5, 'a'},
+		{rosetta.BlockRemoved, 4, 'a'},
+		{rosetta.BlockAdded, 4, 'b'},
+		{rosetta.BlockAdded, 5, 'b'},
+		{rosetta.BlockAdded, 6, 'b'},
+		{rosetta.BlockAdded, 7, 'b'},
+	}
+	maxSequence, recorded := events.Range(0, 100)
+	if maxSequence != int64(len(expected))-1 || len(recorded) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(recorded))
+	}
+	for i, e := range expected {
+		hash := typesCKB.BytesToHash([]byte{e.branch, byte(e.index)}).String()
+		event := recorded[i]
+		if event.Sequence != int64(i) || event.Type != e.eventType || event.BlockIdentifier.Index != e.index || event.BlockIdentifier.Hash != hash {
+			t.Errorf("event %d: expected %s %d %s, got %s %d %s", i, e.eventType, e.index, hash, event.Type, event.BlockIdentifier.Index, event.BlockIdentifier.Hash)
+		}
+	}
+}
+
+func TestOpenBlockEvents(t *testing.T) {
+	complete := `{"sequence":0,"block_identifier":{"index":3,"hash":"0x03"},"type":"block_added"}` + "\n" +
+		`{"sequence":1,"block_identifier":{"index":4,"hash":"0x04"},"type":"block_added"}` + "\n"
+	tests := []struct {
+		name    string
+		content string
+		events  int
+		err     bool
+	}{
+		{
+			name:    "new log",
+			content: "",
+		},
+		{
+			name:    "complete log",
+			content: complete,
+			events:  2,
+		},
+		{
+			name:    "half written last event",
+			content: complete + `{"sequence":2,"block_identifier":{"index":5,`,
+			events:  2,
+		},
+		{
+			name:    "corrupt event",
+			content: `{"sequence":0,"block_identifier":` + "\n" + complete,
+			err:     true,
+		},
+		{
+			name:    "sequence gap",
+			content: `{"sequence":1,"block_identifier":{"index":4,"hash":"0x04"},"type":"block_added"}` + "\n",
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "events")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "events.log")
+			if test.content != "" {
+				if err = ioutil.WriteFile(path, []byte(test.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			events, err := OpenBlockEvents(path)
+			if test.err {
+				if err == nil {
+					events.Close()
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer events.Close()
+			if maxSequence, _ := events.Range(0, maxEventsLimit); maxSequence != int64(test.events)-1 {
+				t.Fatalf("expected %d events, got %d", test.events, maxSequence+1)
+			}
+
+			// the next event follows the complete ones, the half written one being truncated
+			if err = events.add(rosetta.BlockAdded, &types.BlockIdentifier{Index: 5, Hash: "0x05"}); err != nil {
+				t.Fatal(err)
+			}
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+			if len(lines) != test.events+1 {
+				t.Fatalf("expected %d lines, got %q", test.events+1, data)
+			}
+			var event rosetta.BlockEvent
+			if err = json.Unmarshal([]byte(lines[test.events]), &event); err != nil || event.Sequence != int64(test.events) {
+				t.Errorf("expected event %d on the last line, got %q", test.events, lines[test.events])
+			}
+		})
+	}
+}
+
+func TestEventsBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	events, err := OpenBlockEvents(filepath.Join(dir, "events.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer events.Close()
+	for i := int64(0); i < 3; i++ {
+		if err = events.add(rosetta.BlockAdded, &types.BlockIdentifier{Index: i, Hash: fmt.Sprintf("0x%02x", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pointer := func(i int64) *int64 { return &i }
+	tests := []struct {
+		name      string
+		events    *BlockEvents
+		offset    *int64
+		limit     *int64
+		sequences []int64
+		err       *rosetta.Error
+	}{
+		{
+			name:      "default range",
+			events:    events,
+			sequences: []int64{0, 1, 2},
+		},
+		{
+			name:      "offset and limit",
+			events:    events,
+			offset:    pointer(1),
+			limit:     pointer(1),
+			sequences: []int64{1},
+		},
+		{
+			name:      "offset past the last event",
+			events:    events,
+			offset:    pointer(3),
+			sequences: []int64{},
+		},
+		{
+			name:   "negative offset",
+			events: events,
+			offset: pointer(-1),
+			err:    EventsError,
+		},
+		{
+			name:   "zero limit",
+			events: events,
+			limit:  pointer(0),
+			err:    EventsError,
+		},
+		{
+			name:   "limit above the maximum",
+			events: events,
+			limit:  pointer(maxEventsLimit + 1),
+			err:    EventsError,
+		},
+		{
+			name: "no events recorded",
+			err:  NoImplementError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := NewEventsAPIService(mainnet, test.events)
+			response, err := service.EventsBlocks(context.Background(), &rosetta.EventsBlocksRequest{
+				NetworkIdentifier: mainnet,
+				Offset:            test.offset,
+				Limit:             test.limit,
+			})
+			assertError(t, test.err, err)
+			if test.err != nil {
+				return
+			}
+
+			if response.MaxSequence != 2 || len(response.Events) != len(test.sequences) {
+				t.Fatalf("expected events %v of 3, got %d of %d", test.sequences, len(response.Events), response.MaxSequence+1)
+			}
+			for i, sequence := range test.sequences {
+				if response.Events[i].Sequence != sequence {
+					t.Errorf("event %d: expected sequence %d, got %d", i, sequence, response.Events[i].Sequence)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	ethRpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/ququzone/ckb-rich-sdk-go/rpc"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// The block fixtures in testdata are synthetic: they follow the structure of mainnet blocks, but
+// their hashes, cellbase rewards and DAO fields were written by hand and are only consistent with
+// each other. With -record, the fixture servers forward every call to a live rich node instead and
+// replace each exchange in the fixture it was served from, keeping the exchanges of the other tests
+// sharing the fixture. The goldens are then regenerated from the recorded blocks:
+//
+//	go test -run 'TestBlock|TestAccount|TestSearch' -record http://localhost:8117 -update
+//
+// The mempool fixture puts a transaction of the block fixture back into the pool, as no pool of a
+// live node can be recorded twice alike.
+var record = flag.String("record", "", "record the exchanges with the rich node at this url into the fixtures")
+
+var mainnet = &types.NetworkIdentifier{
+	Blockchain: "CKB",
+	Network:    "Mainnet",
+}
+
+// exchange is a recorded json-rpc call of the node or the indexer.
+type exchange struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// newFixtureClient returns a ChainClient answering from the exchanges recorded in the testdata
+// fixtures. Calls which were not recorded return null.
+func newFixtureClient(t *testing.T, fixtures ...string) ChainClient {
+	return dialFixtureClient(t, newFixtureServer(t, fixtures...))
+}
+
+// dialFixtureClient returns a ChainClient connected to the fixture server at url.
+func dialFixtureClient(t *testing.T, url string) ChainClient {
+	client, err := rpc.Dial(url+"/rpc", url+"/indexer")
+	if err != nil {
+		t.Fatalf("dial fixture server: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+// dialFixtureNode connects the raw node rpc client of the helpers to the fixture server at url.
+func dialFixtureNode(t *testing.T, url string) *ethRpc.Client {
+	client, err := ethRpc.Dial(url + "/rpc")
+	if err != nil {
+		t.Fatalf("dial fixture server: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+// newFixtureServer starts a json-rpc server answering from the exchanges recorded in the testdata
+// fixtures, the first matching exchange winning, and returns its url. With -record, it answers
+// from the rich node instead and records each exchange into the fixture it was served from.
+func newFixtureServer(t *testing.T, fixtures ...string) string {
+	recorded := make([][]*exchange, len(fixtures))
+	for i, fixture := range fixtures {
+		file, err := ioutil.ReadFile(filepath.Join("testdata", fixture+".json"))
+		if err != nil {
+			t.Fatalf("read fixture %s: %v", fixture, err)
+		}
+		if err = json.Unmarshal(file, &recorded[i]); err != nil {
+			t.Fatalf("decode fixture %s: %v", fixture, err)
+		}
+	}
+
+	var mu sync.Mutex
+	changed := make([]bool, len(fixtures))
+	answer := func(path string, request *rpcRequest) *rpcResponse {
+		mu.Lock()
+		defer mu.Unlock()
+
+		fixture, index := -1, -1
+	find:
+		for i, exchanges := range recorded {
+			for j, e := range exchanges {
+				if e.Method == request.Method && sameJSON(e.Params, request.Params) {
+					fixture, index = i, j
+					break find
+				}
+			}
+		}
+
+		response := &rpcResponse{JSONRPC: "2.0", ID: request.ID}
+		var e *exchange
+		switch {
+		// the failures of the node recorded as errors are replayed, as they cannot be reproduced
+		case *record != "" && (fixture < 0 || recorded[fixture][index].Error == nil):
+			var err error
+			if e, err = forward(*record+path, request); err != nil {
+				t.Errorf("forward %s: %v", request.Method, err)
+				response.Error = &rpcError{Code: -32000, Message: err.Error()}
+				return response
+			}
+			// a call no fixture served is recorded into the first fixture of the test
+			if fixture < 0 {
+				fixture, index = 0, len(recorded[0])
+				recorded[0] = append(recorded[0], e)
+			}
+			recorded[fixture][index] = e
+			changed[fixture] = true
+		case fixture >= 0:
+			e = recorded[fixture][index]
+		default:
+			// like the node, which returns no block, header or transaction for an unknown one
+			t.Logf("no fixture for %s %s", request.Method, request.Params)
+			e = &exchange{}
+		}
+
+		response.Result = e.Result
+		response.Error = e.Error
+		if response.Result == nil && response.Error == nil {
+			response.Result = json.RawMessage("null")
+		}
+		return response
+	}
+
+	url := serveRpc(t, answer)
+	if *record != "" {
+		t.Cleanup(func() {
+			mu.Lock()
+			defer mu.Unlock()
+			for i, fixture := range fixtures {
+				if !changed[i] {
+					continue
+				}
+				encoded, err := json.MarshalIndent(recorded[i], "", "  ")
+				if err != nil {
+					t.Fatalf("encode fixture %s: %v", fixture, err)
+				}
+				if err = ioutil.WriteFile(filepath.Join("testdata", fixture+".json"), append(encoded, '\n'), 0644); err != nil {
+					t.Fatalf("write fixture %s: %v", fixture, err)
+				}
+			}
+		})
+	}
+
+	return url
+}
+
+// forward calls the method of request on the rich node at url and returns the exchange.
+func forward(url string, request *rpcRequest) (*exchange, error) {
+	var response rpcResponse
+	if err := callRpc(url, request, &response); err != nil {
+		return nil, err
+	}
+
+	e := &exchange{Method: request.Method, Params: request.Params, Error: response.Error}
+	if !bytes.Equal(response.Result, []byte("null")) {
+		e.Result = response.Result
+	}
+	return e, nil
+}
+
+// callRpc calls the method of request at url, decoding the response into response.
+func callRpc(url string, request *rpcRequest, response *rpcResponse) error {
+	body, err := json.Marshal(&rpcRequest{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage("1"),
+		Method:  request.Method,
+		Params:  request.Params,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+// serveRpc starts a json-rpc server answering single and batched calls with answer, given the path
+// of the call, /rpc for the node and /indexer for the indexer, and returns its url.
+func serveRpc(t *testing.T, answer func(path string, request *rpcRequest) *rpcResponse) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+			var requests []*rpcRequest
+			if err = json.Unmarshal(body, &requests); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			responses := make([]*rpcResponse, len(requests))
+			for i, request := range requests {
+				responses[i] = answer(r.URL.Path, request)
+			}
+			_ = json.NewEncoder(w).Encode(responses)
+			return
+		}
+		var request rpcRequest
+		if err = json.Unmarshal(body, &request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(answer(r.URL.Path, &request))
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+// sameJSON reports whether a and b encode the same value, an absent value being null.
+func sameJSON(a json.RawMessage, b json.RawMessage) bool {
+	var x, y interface{}
+	if len(a) > 0 && json.Unmarshal(a, &x) != nil {
+		return false
+	}
+	if len(b) > 0 && json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// assertJSON fails t if actual does not encode to the same json as the golden file, which is
+// rewritten instead when the tests run with -update.
+func assertJSON(t *testing.T, golden string, actual interface{}) {
+	path := filepath.Join("testdata", golden+".json")
+	encoded, err := json.MarshalIndent(actual, "", "  ")
+	if err != nil {
+		t.Fatalf("encode result: %v", err)
+	}
+	if *update {
+		if err = ioutil.WriteFile(path, append(encoded, '\n'), 0644); err != nil {
+			t.Fatalf("write golden %s: %v", golden, err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden %s: %v", golden, err)
+	}
+	if !sameJSON(expected, encoded) {
+		t.Errorf("result differs from %s:\n%s", golden, encoded)
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+// MempoolAPIService implements the rosetta.MempoolAPIServicer interface.
+type MempoolAPIService struct {
+	network *types.NetworkIdentifier
+	client  ChainClient
+	pool    TxPool
+	mapper  *transactionMapper
+}
+
+// NewMempoolAPIService creates a new instance of a MempoolAPIService.
+func NewMempoolAPIService(network *types.NetworkIdentifier, client ChainClient, pool TxPool, udts *UdtRegistry) rosetta.MempoolAPIServicer {
+	return &MempoolAPIService{
+		network: network,
+		client:  client,
+		pool:    pool,
+		mapper: &transactionMapper{
+			network: network,
+			client:  client,
+			udts:    udts,
+		},
+	}
+}
+
+// Mempool implements the /mempool endpoint.
+func (s *MempoolAPIService) Mempool(
+	ctx context.Context,
+	request *types.MempoolRequest,
+) (*types.MempoolResponse, *rosetta.Error) {
+	pool, err := s.pool.GetRawTxPool(ctx)
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+
+	identifiers := make([]*types.TransactionIdentifier, 0, len(pool.Pending)+len(pool.Proposed))
+	for _, hashes := range [][]typesCKB.Hash{pool.Pending, pool.Proposed} {
+		for _, hash := range hashes {
+			identifiers = append(identifiers, &types.TransactionIdentifier{
+				Hash: hash.String(),
+			})
+		}
+	}
+
+	return &types.MempoolResponse{
+		TransactionIdentifiers: identifiers,
+	}, nil
+}
+
+// MempoolTransaction implements the /mempool/transaction endpoint.
+func (s *MempoolAPIService) MempoolTransaction(
+	ctx context.Context,
+	request *types.MempoolTransactionRequest,
+) (*types.MempoolTransactionResponse, *rosetta.Error) {
+	tx, err := s.client.GetTransaction(ctx, typesCKB.HexToHash(request.TransactionIdentifier.Hash))
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+	if tx == nil || tx.TxStatus == nil ||
+		(tx.TxStatus.Status != typesCKB.TransactionStatusPending && tx.TxStatus.Status != typesCKB.TransactionStatusProposed) {
+		return nil, MempoolTransactionError
+	}
+
+	transaction := &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: tx.Transaction.Hash.String(),
+		},
+		Operations: []*types.Operation{},
+	}
+	// inputs may be created by transactions still in the pool, which get_transaction returns as well
+	inputTxCache, err := fetchInputTransactions(ctx, s.client, []*typesCKB.Transaction{tx.Transaction})
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+	_, err = s.mapper.processTransaction(ctx, tx.Transaction, inputTxCache, 0, transaction)
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+
+	return &types.MempoolTransactionResponse{
+		Transaction: transaction,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+)
+
+func TestMempool(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures []string
+		hashes   []string
+		err      *rosetta.Error
+	}{
+		{
+			name:     "pending and proposed",
+			fixtures: []string{"mempool"},
+			hashes: []string{
+				"0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+				"0x4f0b7d2c9a1e8356b0c4d7e2f9a3b6c1d5e8f0a2b4c6d8e0f1a3b5c7d9e1f3a5",
+			},
+		},
+		{
+			name:     "node unavailable",
+			fixtures: []string{"mempool_unavailable"},
+			err:      RpcError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := newFixtureServer(t, test.fixtures...)
+			service := NewMempoolAPIService(mainnet, dialFixtureClient(t, url), NewTxPool(dialFixtureNode(t, url)), nil)
+			response, err := service.Mempool(context.Background(), &types.MempoolRequest{
+				NetworkIdentifier: mainnet,
+			})
+			assertError(t, test.err, err)
+			if test.err != nil {
+				return
+			}
+
+			if len(response.TransactionIdentifiers) != len(test.hashes) {
+				t.Fatalf("transactions %v, want %v", response.TransactionIdentifiers, test.hashes)
+			}
+			for i, identifier := range response.TransactionIdentifiers {
+				if identifier.Hash != test.hashes[i] {
+					t.Errorf("transaction %d is %s, want %s", i, identifier.Hash, test.hashes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMempoolTransaction(t *testing.T) {
+	tests := []struct {
+		name   string
+		hash   string
+		golden string
+		err    *rosetta.Error
+	}{
+		{
+			name:   "pending transaction",
+			hash:   "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+			golden: "mempool_transaction_response",
+		},
+		{
+			name: "committed transaction",
+			hash: "0xa332cfb34620c051cbef0cc82ac6fdbc0061b1681f091924b20134df75177f1d",
+			err:  MempoolTransactionError,
+		},
+		{
+			name: "unknown transaction",
+			hash: "0x0000000000000000000000000000000000000000000000000000000000000001",
+			err:  MempoolTransactionError,
+		},
+	}
+
+	// the pending transaction spends a cell of a transaction of the block fixture
+	url := newFixtureServer(t, "mempool", "block")
+	service := NewMempoolAPIService(mainnet, dialFixtureClient(t, url), NewTxPool(dialFixtureNode(t, url)), nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, err := service.MempoolTransaction(context.Background(), &types.MempoolTransactionRequest{
+				NetworkIdentifier:     mainnet,
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: test.hash},
+			})
+			assertError(t, test.err, err)
+			if test.golden != "" {
+				assertJSON(t, test.golden, response)
+			}
+		})
+	}
+}
@@ -1,49 +1,65 @@
 package services
 
 import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
 	"github.com/ququzone/ckb-sdk-go/address"
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
 	typesCKB "github.com/ququzone/ckb-sdk-go/types"
 )
 
 var (
-	NoImplementError = &types.Error{
-		Code:      1,
-		Message:   "not implemented",
-		Retriable: false,
+	CkbCurrency = &types.Currency{
+		Symbol:   "CKB",
+		Decimals: 8,
 	}
 
-	RpcError = &types.Error{
-		Code:      2,
-		Message:   "rpc error",
-		Retriable: true,
+	// FeeAccount is the pseudo account holding transaction fees from the moment a transaction is
+	// committed until the fees are paid out to miners by a later cellbase.
+	FeeAccount = &types.AccountIdentifier{
+		Address: "fee",
 	}
 
-	AddressError = &types.Error{
-		Code:      3,
-		Message:   "address error",
-		Retriable: false,
+	// DaoAccount is the pseudo account the compensation of Nervos DAO withdrawals is minted from,
+	// so that a withdrawn cell is spent for its own capacity.
+	DaoAccount = &types.AccountIdentifier{
+		Address: "dao",
 	}
 
-	SubmitError = &types.Error{
-		Code:      4,
-		Message:   "submit transaction error",
-		Retriable: true,
-	}
+	// PseudoAccounts are the accounts operations balance against which hold no cells, so they have
+	// no balance or coins. /network/options lists them as exempt from balance reconciliation.
+	PseudoAccounts = []*types.AccountIdentifier{FeeAccount, DaoAccount}
+)
 
-	ServerError = &types.Error{
-		Code:      5,
-		Message:   "server error",
-		Retriable: true,
-	}
+var (
+	errBlockMismatch          = errors.New("block hash and index mismatch")
+	errBlockNotFound          = errors.New("block not found")
+	errHashMismatch           = errors.New("transaction hash mismatch")
+	errIndexerBehind          = errors.New("indexer behind the requested block")
+	errInvalidBlockIdentifier = errors.New("invalid block identifier")
+	errInvalidCursor          = errors.New("invalid cursor")
+	errTipMoved               = errors.New("tip kept moving while listing the cells")
+)
 
-	CkbCurrency = &types.Currency{
-		Symbol:   "CKB",
-		Decimals: 8,
+// isPseudoAccount reports whether address is the address of one of the PseudoAccounts.
+func isPseudoAccount(address string) bool {
+	for _, account := range PseudoAccounts {
+		if account.Address == address {
+			return true
+		}
 	}
-)
+
+	return false
+}
 
 func GenerateAddress(network *types.NetworkIdentifier, script *typesCKB.Script) string {
 	var mode = address.Mainnet
@@ -58,3 +74,136 @@ func GenerateAddress(network *types.NetworkIdentifier, script *typesCKB.Script)
 
 	return addr
 }
+
+// Secp256k1Address returns the short address on network of the secp256k1 blake160 lock of
+// publicKey, a compressed secp256k1 public key, along with the lock.
+func Secp256k1Address(network *types.NetworkIdentifier, publicKey []byte) (string, *typesCKB.Script, error) {
+	if len(publicKey) != 33 {
+		return "", nil, fmt.Errorf("public key of %d bytes is not compressed", len(publicKey))
+	}
+	if _, err := crypto.DecompressPubkey(publicKey); err != nil {
+		return "", nil, err
+	}
+
+	args, err := blake2b.Blake160(publicKey)
+	if err != nil {
+		return "", nil, err
+	}
+	lock := secp256k1Lock(args)
+
+	return GenerateAddress(network, lock), lock, nil
+}
+
+// coinIdentifier identifies the cell created at index of the transaction with hash.
+func coinIdentifier(hash typesCKB.Hash, index uint) string {
+	return fmt.Sprintf("%s:%d", hash.String(), index)
+}
+
+// coinChange returns the metadata recording that an operation applies action to the cell created
+// at index of the transaction with hash. Rosetta 1.3.0 has no coin_change field on operations.
+func coinChange(hash typesCKB.Hash, index uint, action string) map[string]interface{} {
+	return map[string]interface{}{
+		"coin_change": &rosetta.CoinChange{
+			CoinIdentifier: &rosetta.CoinIdentifier{
+				Identifier: coinIdentifier(hash, index),
+			},
+			CoinAction: action,
+		},
+	}
+}
+
+func parseCoinIdentifier(identifier string) (*typesCKB.OutPoint, error) {
+	parts := strings.Split(identifier, ":")
+	if len(parts) != 2 || len(parts[0]) != 66 {
+		return nil, fmt.Errorf("invalid coin identifier %s", identifier)
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	return &typesCKB.OutPoint{
+		TxHash: typesCKB.HexToHash(parts[0]),
+		Index:  uint(index),
+	}, nil
+}
+
+// finalBlock returns the number of the block depth blocks below tip, or genesis on a shorter chain.
+func finalBlock(tip uint64, depth uint64) uint64 {
+	if tip < depth {
+		return 0
+	}
+
+	return tip - depth
+}
+
+// isHash reports whether value is a 0x prefixed hex encoded 32 bytes hash.
+func isHash(value string) bool {
+	if len(value) != 66 || !strings.HasPrefix(value, "0x") {
+		return false
+	}
+	_, err := hex.DecodeString(value[2:])
+
+	return err == nil
+}
+
+// fetchInputTransactions fetches the transactions which create the cells consumed by txs, keyed by
+// transaction hash.
+func fetchInputTransactions(ctx context.Context, client ChainClient, txs []*typesCKB.Transaction) (map[string]*typesCKB.TransactionWithStatus, error) {
+	hashes := make([]typesCKB.Hash, 0)
+	for _, tx := range txs {
+		for _, input := range tx.Inputs {
+			hashes = append(hashes, input.PreviousOutput.TxHash)
+		}
+	}
+
+	return batchTransactions(ctx, client, hashes)
+}
+
+// batchTransactions fetches the transactions of hashes, keyed by transaction hash. Duplicated hashes
+// are fetched once and requests are batched to at most 2000 transactions each.
+func batchTransactions(ctx context.Context, client ChainClient, hashes []typesCKB.Hash) (map[string]*typesCKB.TransactionWithStatus, error) {
+	batchReq := make([]typesCKB.BatchTransactionItem, 0)
+	txHashCache := make(map[string]bool)
+	for _, hash := range hashes {
+		if _, ok := txHashCache[hash.String()]; !ok {
+			txHashCache[hash.String()] = true
+			batchReq = append(batchReq, typesCKB.BatchTransactionItem{
+				Hash:   hash,
+				Result: &typesCKB.TransactionWithStatus{},
+			})
+		}
+	}
+
+	if len(batchReq) > 0 {
+		count := len(batchReq) / 2000
+		if len(batchReq)%2000 != 0 {
+			count++
+		}
+
+		for i := 0; i < count; i++ {
+			start := i * 2000
+			end := start + 2000
+			if i == count-1 {
+				end = len(batchReq)
+			}
+			err := client.BatchTransactions(ctx, batchReq[start:end])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	txCache := make(map[string]*typesCKB.TransactionWithStatus)
+	for _, req := range batchReq {
+		if req.Error != nil {
+			return nil, req.Error
+		}
+		if req.Result.Transaction == nil {
+			return nil, fmt.Errorf("transaction %s not found", req.Hash.String())
+		}
+		txCache[req.Hash.String()] = req.Result
+	}
+
+	return txCache, nil
+}
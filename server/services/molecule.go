@@ -0,0 +1,365 @@
+package services
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ququzone/ckb-sdk-go/crypto/blake2b"
+	"github.com/ququzone/ckb-sdk-go/types"
+)
+
+// The molecule encoding of CKB structures, as defined by the blockchain schema of CKB. The ckb-sdk
+// encodes a Script, CellOutput, WitnessArgs and RawTransaction, whose encoding is the Serialize
+// method of a Transaction, but neither decodes them nor encodes a Transaction with its witnesses.
+
+const (
+	moleculeNumberSize = 4
+	outPointSize       = types.HashLength + 4
+	cellInputSize      = 8 + outPointSize
+	cellDepSize        = outPointSize + 1
+)
+
+// SerializeTransaction returns the molecule encoding of tx with its witnesses.
+func SerializeTransaction(tx *types.Transaction) ([]byte, error) {
+	raw, err := tx.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	witnesses := make([][]byte, len(tx.Witnesses))
+	for i, witness := range tx.Witnesses {
+		witnesses[i] = types.SerializeBytes(witness)
+	}
+
+	return types.SerializeTable([][]byte{raw, types.SerializeDynVec(witnesses)}), nil
+}
+
+// WitnessHash returns the hash of tx with its witnesses, which commits to the witnesses unlike the
+// transaction hash.
+func WitnessHash(tx *types.Transaction) (types.Hash, error) {
+	data, err := SerializeTransaction(tx)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	hash, err := blake2b.Blake256(data)
+	if err != nil {
+		return types.Hash{}, err
+	}
+
+	return types.BytesToHash(hash), nil
+}
+
+// TransactionSize returns the size tx occupies in a block, its encoding and the offset of the
+// block locating it, which the fee rate of tx is relative to.
+func TransactionSize(tx *types.Transaction) (uint64, error) {
+	data, err := SerializeTransaction(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(data)) + moleculeNumberSize, nil
+}
+
+// DeserializeTransaction decodes the molecule encoding of a transaction with its witnesses.
+func DeserializeTransaction(data []byte) (*types.Transaction, error) {
+	fields, err := moleculeTable(data, 2)
+	if err != nil {
+		return nil, fmt.Errorf("transaction: %v", err)
+	}
+
+	tx, err := DeserializeRawTransaction(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	witnesses, err := moleculeDynVec(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("witnesses: %v", err)
+	}
+	tx.Witnesses = make([][]byte, len(witnesses))
+	for i, witness := range witnesses {
+		if tx.Witnesses[i], err = moleculeBytes(witness); err != nil {
+			return nil, fmt.Errorf("witness %d: %v", i, err)
+		}
+	}
+
+	return tx, nil
+}
+
+// DeserializeRawTransaction decodes the molecule encoding of a transaction without its witnesses.
+// The hash of the transaction is computed from data.
+func DeserializeRawTransaction(data []byte) (*types.Transaction, error) {
+	fields, err := moleculeTable(data, 6)
+	if err != nil {
+		return nil, fmt.Errorf("raw transaction: %v", err)
+	}
+	if len(fields[0]) != moleculeNumberSize {
+		return nil, errors.New("version: invalid size")
+	}
+
+	hash, err := blake2b.Blake256(data)
+	if err != nil {
+		return nil, err
+	}
+	tx := &types.Transaction{
+		Version:   uint(binary.LittleEndian.Uint32(fields[0])),
+		Hash:      types.BytesToHash(hash),
+		Witnesses: [][]byte{},
+	}
+
+	deps, err := moleculeFixVec(fields[1], cellDepSize)
+	if err != nil {
+		return nil, fmt.Errorf("cell deps: %v", err)
+	}
+	tx.CellDeps = make([]*types.CellDep, len(deps))
+	for i, dep := range deps {
+		if tx.CellDeps[i], err = deserializeCellDep(dep); err != nil {
+			return nil, fmt.Errorf("cell dep %d: %v", i, err)
+		}
+	}
+
+	headers, err := moleculeFixVec(fields[2], types.HashLength)
+	if err != nil {
+		return nil, fmt.Errorf("header deps: %v", err)
+	}
+	tx.HeaderDeps = make([]types.Hash, len(headers))
+	for i, header := range headers {
+		tx.HeaderDeps[i] = types.BytesToHash(header)
+	}
+
+	inputs, err := moleculeFixVec(fields[3], cellInputSize)
+	if err != nil {
+		return nil, fmt.Errorf("inputs: %v", err)
+	}
+	tx.Inputs = make([]*types.CellInput, len(inputs))
+	for i, input := range inputs {
+		tx.Inputs[i] = &types.CellInput{
+			Since:          binary.LittleEndian.Uint64(input),
+			PreviousOutput: deserializeOutPoint(input[8:]),
+		}
+	}
+
+	outputs, err := moleculeDynVec(fields[4])
+	if err != nil {
+		return nil, fmt.Errorf("outputs: %v", err)
+	}
+	tx.Outputs = make([]*types.CellOutput, len(outputs))
+	for i, output := range outputs {
+		if tx.Outputs[i], err = DeserializeCellOutput(output); err != nil {
+			return nil, fmt.Errorf("output %d: %v", i, err)
+		}
+	}
+
+	outputsData, err := moleculeDynVec(fields[5])
+	if err != nil {
+		return nil, fmt.Errorf("outputs data: %v", err)
+	}
+	tx.OutputsData = make([][]byte, len(outputsData))
+	for i, data := range outputsData {
+		if tx.OutputsData[i], err = moleculeBytes(data); err != nil {
+			return nil, fmt.Errorf("outputs data %d: %v", i, err)
+		}
+	}
+
+	return tx, nil
+}
+
+// DeserializeCellOutput decodes the molecule encoding of a cell output.
+func DeserializeCellOutput(data []byte) (*types.CellOutput, error) {
+	fields, err := moleculeTable(data, 3)
+	if err != nil {
+		return nil, fmt.Errorf("cell output: %v", err)
+	}
+	if len(fields[0]) != 8 {
+		return nil, errors.New("capacity: invalid size")
+	}
+
+	output := &types.CellOutput{
+		Capacity: binary.LittleEndian.Uint64(fields[0]),
+	}
+	if output.Lock, err = DeserializeScript(fields[1]); err != nil {
+		return nil, fmt.Errorf("lock: %v", err)
+	}
+	if len(fields[2]) > 0 {
+		if output.Type, err = DeserializeScript(fields[2]); err != nil {
+			return nil, fmt.Errorf("type: %v", err)
+		}
+	}
+
+	return output, nil
+}
+
+// DeserializeScript decodes the molecule encoding of a script.
+func DeserializeScript(data []byte) (*types.Script, error) {
+	fields, err := moleculeTable(data, 3)
+	if err != nil {
+		return nil, fmt.Errorf("script: %v", err)
+	}
+	if len(fields[0]) != types.HashLength {
+		return nil, errors.New("code hash: invalid size")
+	}
+	if len(fields[1]) != 1 {
+		return nil, errors.New("hash type: invalid size")
+	}
+
+	script := &types.Script{
+		CodeHash: types.BytesToHash(fields[0]),
+	}
+	switch fields[1][0] {
+	case 0:
+		script.HashType = types.HashTypeData
+	case 1:
+		script.HashType = types.HashTypeType
+	default:
+		return nil, fmt.Errorf("invalid hash type %d", fields[1][0])
+	}
+	if script.Args, err = moleculeBytes(fields[2]); err != nil {
+		return nil, fmt.Errorf("args: %v", err)
+	}
+
+	return script, nil
+}
+
+// DeserializeWitnessArgs decodes the molecule encoding of witness args.
+func DeserializeWitnessArgs(data []byte) (*types.WitnessArgs, error) {
+	fields, err := moleculeTable(data, 3)
+	if err != nil {
+		return nil, fmt.Errorf("witness args: %v", err)
+	}
+
+	args := &types.WitnessArgs{}
+	if args.Lock, err = moleculeOptionBytes(fields[0]); err != nil {
+		return nil, fmt.Errorf("lock: %v", err)
+	}
+	if args.InputType, err = moleculeOptionBytes(fields[1]); err != nil {
+		return nil, fmt.Errorf("input type: %v", err)
+	}
+	if args.OutputType, err = moleculeOptionBytes(fields[2]); err != nil {
+		return nil, fmt.Errorf("output type: %v", err)
+	}
+
+	return args, nil
+}
+
+func deserializeCellDep(data []byte) (*types.CellDep, error) {
+	dep := &types.CellDep{
+		OutPoint: deserializeOutPoint(data),
+	}
+	switch data[outPointSize] {
+	case 0:
+		dep.DepType = types.DepTypeCode
+	case 1:
+		dep.DepType = types.DepTypeDepGroup
+	default:
+		return nil, fmt.Errorf("invalid dep type %d", data[outPointSize])
+	}
+
+	return dep, nil
+}
+
+func deserializeOutPoint(data []byte) *types.OutPoint {
+	return &types.OutPoint{
+		TxHash: types.BytesToHash(data[:types.HashLength]),
+		Index:  uint(binary.LittleEndian.Uint32(data[types.HashLength:outPointSize])),
+	}
+}
+
+// moleculeTable returns the count fields of the table encoded by data.
+func moleculeTable(data []byte, count int) ([][]byte, error) {
+	fields, err := moleculeOffsets(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != count {
+		return nil, fmt.Errorf("%d fields, expected %d", len(fields), count)
+	}
+
+	return fields, nil
+}
+
+// moleculeDynVec returns the items of the dynamic vector encoded by data.
+func moleculeDynVec(data []byte) ([][]byte, error) {
+	return moleculeOffsets(data)
+}
+
+// moleculeOffsets splits data, a table or dynamic vector, which is its full size followed by the
+// offsets of its items and the items, into its items.
+func moleculeOffsets(data []byte) ([][]byte, error) {
+	if len(data) < moleculeNumberSize {
+		return nil, errors.New("truncated header")
+	}
+	size := binary.LittleEndian.Uint32(data)
+	if uint64(size) != uint64(len(data)) {
+		return nil, fmt.Errorf("size %d, expected %d", len(data), size)
+	}
+	if size == moleculeNumberSize {
+		return [][]byte{}, nil
+	}
+	if size < 2*moleculeNumberSize {
+		return nil, errors.New("truncated header")
+	}
+
+	first := binary.LittleEndian.Uint32(data[moleculeNumberSize:])
+	if first%moleculeNumberSize != 0 || first < 2*moleculeNumberSize || first > size {
+		return nil, fmt.Errorf("invalid first offset %d", first)
+	}
+	count := int(first/moleculeNumberSize) - 1
+
+	offsets := make([]uint32, count+1)
+	for i := 0; i < count; i++ {
+		offsets[i] = binary.LittleEndian.Uint32(data[moleculeNumberSize*(i+1):])
+	}
+	offsets[count] = size
+
+	items := make([][]byte, count)
+	for i := 0; i < count; i++ {
+		if offsets[i] > offsets[i+1] {
+			return nil, fmt.Errorf("invalid offset %d", offsets[i])
+		}
+		items[i] = data[offsets[i]:offsets[i+1]]
+	}
+
+	return items, nil
+}
+
+// moleculeFixVec returns the items of the fixed vector of items of size encoded by data.
+func moleculeFixVec(data []byte, size int) ([][]byte, error) {
+	if len(data) < moleculeNumberSize {
+		return nil, errors.New("truncated header")
+	}
+	count := int(binary.LittleEndian.Uint32(data))
+	if uint64(len(data)-moleculeNumberSize) != uint64(count)*uint64(size) {
+		return nil, fmt.Errorf("size %d for %d items", len(data), count)
+	}
+
+	items := make([][]byte, count)
+	for i := range items {
+		start := moleculeNumberSize + i*size
+		items[i] = data[start : start+size]
+	}
+
+	return items, nil
+}
+
+// moleculeBytes returns the bytes encoded by data.
+func moleculeBytes(data []byte) ([]byte, error) {
+	items, err := moleculeFixVec(data, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, len(items))
+	copy(result, data[moleculeNumberSize:])
+
+	return result, nil
+}
+
+// moleculeOptionBytes returns the optional bytes encoded by data, nil when absent.
+func moleculeOptionBytes(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	return moleculeBytes(data)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+func readSignedTransaction(t *testing.T) *typesCKB.Transaction {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "signed_transaction.json"))
+	if err != nil {
+		t.Fatalf("read signed transaction: %v", err)
+	}
+	tx, err := ToTransaction(string(data))
+	if err != nil {
+		t.Fatalf("decode signed transaction: %v", err)
+	}
+
+	return tx
+}
+
+func TestMoleculeTransaction(t *testing.T) {
+	tx := readSignedTransaction(t)
+
+	data, err := SerializeTransaction(tx)
+	if err != nil {
+		t.Fatalf("serialize: %v", err)
+	}
+	decoded, err := DeserializeTransaction(data)
+	if err != nil {
+		t.Fatalf("deserialize: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, tx) {
+		t.Errorf("expected %+v, got %+v", tx, decoded)
+	}
+	if decoded.Hash.String() != "0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421" {
+		t.Errorf("unexpected hash %s", decoded.Hash.String())
+	}
+
+	witnessHash, err := WitnessHash(tx)
+	if err != nil {
+		t.Fatalf("witness hash: %v", err)
+	}
+	if witnessHash == tx.Hash {
+		t.Error("witness hash does not commit to the witnesses")
+	}
+	size, err := TransactionSize(tx)
+	if err != nil {
+		t.Fatalf("size: %v", err)
+	}
+	if size != uint64(len(data))+4 {
+		t.Errorf("expected size %d, got %d", len(data)+4, size)
+	}
+
+	for _, truncated := range [][]byte{data[:3], data[:len(data)-1], data[:20]} {
+		if _, err := DeserializeTransaction(truncated); err == nil {
+			t.Errorf("decoded truncated transaction of %d bytes", len(truncated))
+		}
+	}
+}
+
+func TestMoleculeCellOutput(t *testing.T) {
+	tx := readSignedTransaction(t)
+
+	for i, output := range tx.Outputs {
+		data, err := output.Serialize()
+		if err != nil {
+			t.Fatalf("serialize output %d: %v", i, err)
+		}
+		decoded, err := DeserializeCellOutput(data)
+		if err != nil {
+			t.Fatalf("deserialize output %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(decoded, output) {
+			t.Errorf("expected output %d %+v, got %+v", i, output, decoded)
+		}
+	}
+
+	script, err := tx.Outputs[0].Lock.Serialize()
+	if err != nil {
+		t.Fatalf("serialize script: %v", err)
+	}
+	// the hash type follows the code hash after the header of the table of three fields
+	script[16+typesCKB.HashLength] = 2
+	if _, err := DeserializeScript(script); err == nil {
+		t.Error("decoded script with an invalid hash type")
+	}
+}
+
+func TestMoleculeWitnessArgs(t *testing.T) {
+	tx := readSignedTransaction(t)
+
+	args, err := DeserializeWitnessArgs(tx.Witnesses[0])
+	if err != nil {
+		t.Fatalf("deserialize: %v", err)
+	}
+	if len(args.Lock) != 65 || args.InputType != nil || args.OutputType != nil {
+		t.Errorf("unexpected witness args %+v", args)
+	}
+	data, err := args.Serialize()
+	if err != nil {
+		t.Fatalf("serialize: %v", err)
+	}
+	if !bytes.Equal(data, tx.Witnesses[0]) {
+		t.Errorf("expected %x, got %x", tx.Witnesses[0], data)
+	}
+}
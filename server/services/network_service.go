@@ -3,22 +3,27 @@ package services
 import (
 	"context"
 
-	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
-	"github.com/ququzone/ckb-rich-sdk-go/rpc"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
 )
 
-// NetworkAPIService implements the server.NetworkAPIServicer interface.
+// NetworkAPIService implements the rosetta.NetworkAPIServicer interface.
 type NetworkAPIService struct {
 	network *types.NetworkIdentifier
-	client  rpc.Client
+	client  ChainClient
+	node    NodeStatus
+	depth   uint64
 }
 
-// NewNetworkAPIService creates a new instance of a NetworkAPIService.
-func NewNetworkAPIService(network *types.NetworkIdentifier, client rpc.Client) server.NetworkAPIServicer {
+// NewNetworkAPIService creates a new instance of a NetworkAPIService. The current block reported is
+// depth blocks below the tip.
+func NewNetworkAPIService(network *types.NetworkIdentifier, client ChainClient, node NodeStatus, depth uint64) rosetta.NetworkAPIServicer {
 	return &NetworkAPIService{
 		network: network,
 		client:  client,
+		node:    node,
+		depth:   depth,
 	}
 }
 
@@ -26,7 +31,7 @@ func NewNetworkAPIService(network *types.NetworkIdentifier, client rpc.Client) s
 func (s *NetworkAPIService) NetworkList(
 	ctx context.Context,
 	request *types.MetadataRequest,
-) (*types.NetworkListResponse, *types.Error) {
+) (*types.NetworkListResponse, *rosetta.Error) {
 	return &types.NetworkListResponse{
 		NetworkIdentifiers: []*types.NetworkIdentifier{
 			s.network,
@@ -34,26 +39,38 @@ func (s *NetworkAPIService) NetworkList(
 	}, nil
 }
 
-// NetworkStatus implements the /network/status endpoint.
+// NetworkStatus implements the /network/status endpoint. The current block is the final block of
+// the indexer, which lags the node by the indexer status lag.
 func (s *NetworkAPIService) NetworkStatus(
 	ctx context.Context,
 	request *types.NetworkRequest,
-) (*types.NetworkStatusResponse, *types.Error) {
-	genesis, err := s.client.GetHeaderByNumber(context.Background(), 0)
+) (*rosetta.NetworkStatusResponse, *rosetta.Error) {
+	genesis, err := s.client.GetHeaderByNumber(ctx, 0)
 	if err != nil {
-		return nil, RpcError
+		return nil, wrapError(RpcError, err)
 	}
-	peers, err := s.client.GetPeers(context.Background())
+	peers, err := s.node.GetPeers(ctx)
 	if err != nil {
-		return nil, RpcError
+		return nil, wrapError(RpcError, err)
 	}
-	header, err := s.client.GetTip(context.Background())
+	syncState, err := s.node.SyncState(ctx)
 	if err != nil {
-		return nil, RpcError
+		return nil, wrapError(RpcError, err)
+	}
+	tip, err := s.client.GetTipHeader(ctx)
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+	header, err := s.client.GetTip(ctx)
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+	nodeHeader, err := s.client.GetHeaderByNumber(ctx, finalBlock(header.BlockNumber, s.depth))
+	if err != nil {
+		return nil, wrapError(RpcError, err)
 	}
-	nodeHeader, err := s.client.GetHeaderByNumber(context.Background(), header.BlockNumber)
 
-	result := &types.NetworkStatusResponse{
+	result := &rosetta.NetworkStatusResponse{
 		CurrentBlockIdentifier: &types.BlockIdentifier{
 			Index: int64(nodeHeader.Number),
 			Hash:  nodeHeader.Hash.String(),
@@ -63,34 +80,103 @@ func (s *NetworkAPIService) NetworkStatus(
 			Index: 0,
 			Hash:  genesis.Hash.String(),
 		},
+		SyncStatus: syncStatus(tip, syncState),
+		IndexerStatus: &rosetta.IndexerStatus{
+			TipBlockIdentifier: &types.BlockIdentifier{
+				Index: int64(header.BlockNumber),
+				Hash:  header.BlockHash.String(),
+			},
+			Lag: int64(tip.Number) - int64(header.BlockNumber),
+		},
 		Peers: []*types.Peer{},
 	}
+	if result.IndexerStatus.Lag < 0 {
+		// the node tip was read before the indexer tip
+		result.IndexerStatus.Lag = 0
+	}
 
 	for _, peer := range peers {
 		result.Peers = append(result.Peers, &types.Peer{
-			PeerID: peer.NodeId,
+			PeerID:   peer.NodeId,
+			Metadata: peerMetadata(peer),
 		})
 	}
 
 	return result, nil
 }
 
+// syncStatus reports the progress of the node from its tip towards the best block it knows of.
+func syncStatus(tip *typesCKB.Header, state *SyncState) *rosetta.SyncStatus {
+	target := int64(state.BestKnownBlockNumber)
+	if target < int64(tip.Number) {
+		target = int64(tip.Number)
+	}
+	synced := !state.Ibd && int64(tip.Number) == target
+
+	stage := "synced"
+	if state.Ibd {
+		stage = "initial_block_download"
+	} else if !synced {
+		stage = "block_sync"
+	}
+
+	return &rosetta.SyncStatus{
+		CurrentIndex: int64(tip.Number),
+		TargetIndex:  &target,
+		Stage:        &stage,
+		Synced:       &synced,
+	}
+}
+
+// peerMetadata describes the addresses, version, protocols and connection direction of peer, for
+// which Rosetta has no fields.
+func peerMetadata(peer *RemoteNode) map[string]interface{} {
+	addresses := []string{}
+	for _, address := range peer.Addresses {
+		addresses = append(addresses, address.Address)
+	}
+	protocols := []map[string]interface{}{}
+	for _, protocol := range peer.Protocols {
+		protocols = append(protocols, map[string]interface{}{
+			"id":      uint64(protocol.ID),
+			"version": protocol.Version,
+		})
+	}
+	direction := "inbound"
+	if peer.IsOutbound {
+		direction = "outbound"
+	}
+
+	return map[string]interface{}{
+		"addresses": addresses,
+		"version":   peer.Version,
+		"protocols": protocols,
+		"direction": direction,
+	}
+}
+
 // NetworkOptions implements the /network/options endpoint.
 func (s *NetworkAPIService) NetworkOptions(
 	ctx context.Context,
 	request *types.NetworkRequest,
-) (*types.NetworkOptionsResponse, *types.Error) {
-	node, err := s.client.LocalNodeInfo(context.Background())
+) (*rosetta.NetworkOptionsResponse, *rosetta.Error) {
+	node, err := s.client.LocalNodeInfo(ctx)
 	if err != nil {
-		return nil, RpcError
+		return nil, wrapError(RpcError, err)
 	}
 
-	return &types.NetworkOptionsResponse{
+	return &rosetta.NetworkOptionsResponse{
 		Version: &types.Version{
 			RosettaVersion: "1.3.0",
 			NodeVersion:    node.Version,
+			// Allow has no historical balance lookup flag in Rosetta 1.3.0
+			Metadata: map[string]interface{}{
+				"historical_balance_lookup": true,
+				// operations credit and debit these accounts, which have no balance to reconcile
+				"exempt_accounts": PseudoAccounts,
+			},
 		},
-		Allow: &types.Allow{
+		Allow: &rosetta.Allow{
 			OperationStatuses: []*types.OperationStatus{
 				{
 					Status:     "Success",
@@ -100,13 +186,17 @@ func (s *NetworkAPIService) NetworkOptions(
 			OperationTypes: []string{
 				"Transfer",
 				"Reward",
+				"PrimaryReward",
+				"SecondaryReward",
+				"ProposalReward",
+				"CommitReward",
+				"Fee",
+				"DaoDeposit",
+				"DaoWithdrawPrepare",
+				"DaoWithdraw",
+				"DaoInterest",
 			},
-			Errors: []*types.Error{
-				NoImplementError,
-				RpcError,
-				AddressError,
-				SubmitError,
-			},
+			Errors: Errors(),
 		},
 	}, nil
 }
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+)
+
+func TestNetworkStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures []string
+		depth    uint64
+		golden   string
+		err      *rosetta.Error
+	}{
+		{
+			name:     "synced node",
+			fixtures: []string{"network"},
+			golden:   "network_status_response",
+		},
+		{
+			name:     "syncing node",
+			fixtures: []string{"network_syncing", "network"},
+			golden:   "network_status_syncing_response",
+		},
+		{
+			name:     "final block",
+			fixtures: []string{"network", "account"},
+			depth:    1,
+			golden:   "network_status_final_response",
+		},
+		{
+			name:     "node unavailable",
+			fixtures: []string{"network_unavailable"},
+			err:      RpcError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			url := newFixtureServer(t, test.fixtures...)
+			node := NewNodeStatus(dialFixtureNode(t, url))
+			service := NewNetworkAPIService(mainnet, dialFixtureClient(t, url), node, test.depth)
+			response, serviceErr := service.NetworkStatus(context.Background(), &types.NetworkRequest{
+				NetworkIdentifier: mainnet,
+			})
+			assertError(t, test.err, serviceErr)
+			if test.golden != "" {
+				assertJSON(t, test.golden, response)
+			}
+		})
+	}
+}
+
+func TestNetworkOptionsExemptAccounts(t *testing.T) {
+	service := NewNetworkAPIService(mainnet, newFixtureClient(t, "network"), nil, 0)
+	response, err := service.NetworkOptions(context.Background(), &types.NetworkRequest{
+		NetworkIdentifier: mainnet,
+	})
+	assertError(t, nil, err)
+
+	exempt, _ := response.Version.Metadata["exempt_accounts"].([]*types.AccountIdentifier)
+	if len(exempt) != len(PseudoAccounts) {
+		t.Fatalf("exempt accounts %v, want %v", exempt, PseudoAccounts)
+	}
+	for i, account := range exempt {
+		if account.Address != PseudoAccounts[i].Address {
+			t.Errorf("exempt account %d is %s, want %s", i, account.Address, PseudoAccounts[i].Address)
+		}
+	}
+}
@@ -0,0 +1,421 @@
+package services
+
+import (
+	"context"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/config"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+)
+
+// NetworkServices holds the services of a single network.
+type NetworkServices struct {
+	Network      rosetta.NetworkAPIServicer
+	Block        rosetta.BlockAPIServicer
+	Account      rosetta.AccountAPIServicer
+	Mempool      rosetta.MempoolAPIServicer
+	Search       rosetta.SearchAPIServicer
+	Events       rosetta.EventsAPIServicer
+	Construction rosetta.ConstructionAPIServicer
+}
+
+// Networks implements the api servicers of every endpoint by routing each request to the services
+// of the network named by its network identifier.
+type Networks struct {
+	identifiers []*types.NetworkIdentifier
+	services    map[string]*NetworkServices
+	timeouts    *config.Timeouts
+}
+
+// NewNetworks creates a new instance of Networks serving no network, bounding the time spent on
+// each request by timeouts.
+func NewNetworks(timeouts *config.Timeouts) *Networks {
+	return &Networks{
+		identifiers: []*types.NetworkIdentifier{},
+		services:    map[string]*NetworkServices{},
+		timeouts:    timeouts,
+	}
+}
+
+// Add serves network with services.
+func (n *Networks) Add(network *types.NetworkIdentifier, services *NetworkServices) {
+	n.identifiers = append(n.identifiers, network)
+	n.services[networkKey(network)] = services
+}
+
+// Identifiers returns the identifiers of the networks served, in the order they were added.
+func (n *Networks) Identifiers() []*types.NetworkIdentifier {
+	return n.identifiers
+}
+
+func (n *Networks) lookup(network *types.NetworkIdentifier) (*NetworkServices, *rosetta.Error) {
+	if network == nil {
+		return nil, NetworkError
+	}
+	services, ok := n.services[networkKey(network)]
+	if !ok {
+		return nil, NetworkError
+	}
+
+	return services, nil
+}
+
+// withTimeout derives the context a request to endpoint is served with from ctx.
+func (n *Networks) withTimeout(ctx context.Context, endpoint string) (context.Context, context.CancelFunc) {
+	timeout := n.timeouts.Default
+	if t, ok := n.timeouts.Endpoints[endpoint]; ok {
+		timeout = t
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timeoutError reports err as TimeoutError when it was caused by ctx running out of time.
+func timeoutError(ctx context.Context, err *rosetta.Error) *rosetta.Error {
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return TimeoutError
+	}
+
+	return err
+}
+
+func networkKey(network *types.NetworkIdentifier) string {
+	return network.Blockchain + "/" + network.Network
+}
+
+// NetworkList implements the /network/list endpoint.
+func (n *Networks) NetworkList(
+	ctx context.Context,
+	request *types.MetadataRequest,
+) (*types.NetworkListResponse, *rosetta.Error) {
+	return &types.NetworkListResponse{
+		NetworkIdentifiers: n.identifiers,
+	}, nil
+}
+
+// NetworkStatus implements the /network/status endpoint.
+func (n *Networks) NetworkStatus(
+	ctx context.Context,
+	request *types.NetworkRequest,
+) (*rosetta.NetworkStatusResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/network/status")
+	defer cancel()
+
+	response, err := services.Network.NetworkStatus(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// NetworkOptions implements the /network/options endpoint.
+func (n *Networks) NetworkOptions(
+	ctx context.Context,
+	request *types.NetworkRequest,
+) (*rosetta.NetworkOptionsResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/network/options")
+	defer cancel()
+
+	response, err := services.Network.NetworkOptions(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// Block implements the /block endpoint.
+func (n *Networks) Block(
+	ctx context.Context,
+	request *types.BlockRequest,
+) (*types.BlockResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/block")
+	defer cancel()
+
+	response, err := services.Block.Block(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// BlockTransaction implements the /block/transaction endpoint.
+func (n *Networks) BlockTransaction(
+	ctx context.Context,
+	request *types.BlockTransactionRequest,
+) (*types.BlockTransactionResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/block/transaction")
+	defer cancel()
+
+	response, err := services.Block.BlockTransaction(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// AccountBalance implements the /account/balance endpoint.
+func (n *Networks) AccountBalance(
+	ctx context.Context,
+	request *types.AccountBalanceRequest,
+) (*types.AccountBalanceResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/account/balance")
+	defer cancel()
+
+	response, err := services.Account.AccountBalance(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// AccountCoins implements the /account/coins endpoint.
+func (n *Networks) AccountCoins(
+	ctx context.Context,
+	request *rosetta.AccountCoinsRequest,
+) (*rosetta.AccountCoinsResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/account/coins")
+	defer cancel()
+
+	response, err := services.Account.AccountCoins(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// Mempool implements the /mempool endpoint.
+func (n *Networks) Mempool(
+	ctx context.Context,
+	request *types.MempoolRequest,
+) (*types.MempoolResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/mempool")
+	defer cancel()
+
+	response, err := services.Mempool.Mempool(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// MempoolTransaction implements the /mempool/transaction endpoint.
+func (n *Networks) MempoolTransaction(
+	ctx context.Context,
+	request *types.MempoolTransactionRequest,
+) (*types.MempoolTransactionResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/mempool/transaction")
+	defer cancel()
+
+	response, err := services.Mempool.MempoolTransaction(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// SearchTransactions implements the /search/transactions endpoint.
+func (n *Networks) SearchTransactions(
+	ctx context.Context,
+	request *rosetta.SearchTransactionsRequest,
+) (*rosetta.SearchTransactionsResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/search/transactions")
+	defer cancel()
+
+	response, err := services.Search.SearchTransactions(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// EventsBlocks implements the /events/blocks endpoint.
+func (n *Networks) EventsBlocks(
+	ctx context.Context,
+	request *rosetta.EventsBlocksRequest,
+) (*rosetta.EventsBlocksResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/events/blocks")
+	defer cancel()
+
+	response, err := services.Events.EventsBlocks(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// ConstructionMetadata implements the /construction/metadata endpoint.
+func (n *Networks) ConstructionMetadata(
+	ctx context.Context,
+	request *types.ConstructionMetadataRequest,
+) (*types.ConstructionMetadataResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/construction/metadata")
+	defer cancel()
+
+	response, err := services.Construction.ConstructionMetadata(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// ConstructionSubmit implements the /construction/submit endpoint.
+func (n *Networks) ConstructionSubmit(
+	ctx context.Context,
+	request *types.ConstructionSubmitRequest,
+) (*types.ConstructionSubmitResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/construction/submit")
+	defer cancel()
+
+	response, err := services.Construction.ConstructionSubmit(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// ConstructionDerive implements the /construction/derive endpoint.
+func (n *Networks) ConstructionDerive(
+	ctx context.Context,
+	request *rosetta.ConstructionDeriveRequest,
+) (*rosetta.ConstructionDeriveResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/construction/derive")
+	defer cancel()
+
+	response, err := services.Construction.ConstructionDerive(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// ConstructionPreprocess implements the /construction/preprocess endpoint.
+func (n *Networks) ConstructionPreprocess(
+	ctx context.Context,
+	request *rosetta.ConstructionPreprocessRequest,
+) (*rosetta.ConstructionPreprocessResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/construction/preprocess")
+	defer cancel()
+
+	response, err := services.Construction.ConstructionPreprocess(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// ConstructionPayloads implements the /construction/payloads endpoint.
+func (n *Networks) ConstructionPayloads(
+	ctx context.Context,
+	request *rosetta.ConstructionPayloadsRequest,
+) (*rosetta.ConstructionPayloadsResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/construction/payloads")
+	defer cancel()
+
+	response, err := services.Construction.ConstructionPayloads(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// ConstructionParse implements the /construction/parse endpoint.
+func (n *Networks) ConstructionParse(
+	ctx context.Context,
+	request *rosetta.ConstructionParseRequest,
+) (*rosetta.ConstructionParseResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/construction/parse")
+	defer cancel()
+
+	response, err := services.Construction.ConstructionParse(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// ConstructionCombine implements the /construction/combine endpoint.
+func (n *Networks) ConstructionCombine(
+	ctx context.Context,
+	request *rosetta.ConstructionCombineRequest,
+) (*rosetta.ConstructionCombineResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/construction/combine")
+	defer cancel()
+
+	response, err := services.Construction.ConstructionCombine(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
+
+// ConstructionHash implements the /construction/hash endpoint.
+func (n *Networks) ConstructionHash(
+	ctx context.Context,
+	request *rosetta.ConstructionHashRequest,
+) (*rosetta.TransactionIdentifierResponse, *rosetta.Error) {
+	services, err := n.lookup(request.NetworkIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := n.withTimeout(ctx, "/construction/hash")
+	defer cancel()
+
+	response, err := services.Construction.ConstructionHash(ctx, request)
+
+	return response, timeoutError(ctx, err)
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/config"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+)
+
+// blockStub serves the block named after its network, after delay or when the context of the
+// request is done, as a node call does.
+type blockStub struct {
+	network string
+	delay   time.Duration
+}
+
+func (s *blockStub) Block(ctx context.Context, request *types.BlockRequest) (*types.BlockResponse, *rosetta.Error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, wrapError(RpcError, ctx.Err())
+	}
+
+	return &types.BlockResponse{
+		Block: &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{Hash: s.network},
+		},
+	}, nil
+}
+
+func (s *blockStub) BlockTransaction(
+	ctx context.Context,
+	request *types.BlockTransactionRequest,
+) (*types.BlockTransactionResponse, *rosetta.Error) {
+	return nil, ServerError
+}
+
+func TestNetworks(t *testing.T) {
+	testnet := &types.NetworkIdentifier{
+		Blockchain: "CKB",
+		Network:    "Testnet",
+	}
+	networks := NewNetworks(&config.Timeouts{
+		Endpoints: map[string]time.Duration{
+			"/block": 50 * time.Millisecond,
+		},
+	})
+	networks.Add(mainnet, &NetworkServices{
+		Block:        &blockStub{network: mainnet.Network},
+		Construction: NewConstructionAPIService(mainnet, nil, nil, false),
+	})
+	networks.Add(testnet, &NetworkServices{
+		Block:        &blockStub{network: testnet.Network, delay: time.Second},
+		Construction: NewConstructionAPIService(testnet, nil, nil, false),
+	})
+
+	list, _ := networks.NetworkList(context.Background(), &types.MetadataRequest{})
+	if len(list.NetworkIdentifiers) != 2 || list.NetworkIdentifiers[0] != mainnet || list.NetworkIdentifiers[1] != testnet {
+		t.Errorf("unexpected networks %+v", list.NetworkIdentifiers)
+	}
+
+	publicKey := &rosetta.PublicKey{
+		HexBytes:  "0x03fe6c6d09d1a0f70255cddf25c5ed57d41b5c08822ae710dc10f8c88290e0acdf",
+		CurveType: rosetta.CurveTypeSecp256k1,
+	}
+	tests := []struct {
+		name    string
+		network *types.NetworkIdentifier
+		address string
+		block   string
+		err     *rosetta.Error
+		// blockErr is the error of /block, which only the testnet stub runs past its timeout for
+		blockErr *rosetta.Error
+	}{
+		{
+			name:    "mainnet",
+			network: mainnet,
+			address: "ckb1qyqvsv5240xeh85wvnau2eky8pwrhh4jr8ts6f6daz",
+			block:   "Mainnet",
+		},
+		{
+			name:     "testnet",
+			network:  testnet,
+			address:  "ckt1qyqvsv5240xeh85wvnau2eky8pwrhh4jr8ts8vyj37",
+			blockErr: TimeoutError,
+		},
+		{
+			name:     "unknown network",
+			network:  &types.NetworkIdentifier{Blockchain: "CKB", Network: "Devnet"},
+			err:      NetworkError,
+			blockErr: NetworkError,
+		},
+		{
+			name:     "no network",
+			err:      NetworkError,
+			blockErr: NetworkError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			derived, err := networks.ConstructionDerive(context.Background(), &rosetta.ConstructionDeriveRequest{
+				NetworkIdentifier: test.network,
+				PublicKey:         publicKey,
+			})
+			assertError(t, test.err, err)
+			if test.err == nil && derived.Address != test.address {
+				t.Errorf("expected address %s, got %s", test.address, derived.Address)
+			}
+
+			block, err := networks.Block(context.Background(), &types.BlockRequest{
+				NetworkIdentifier: test.network,
+			})
+			assertError(t, test.blockErr, err)
+			if test.blockErr == nil && block.Block.BlockIdentifier.Hash != test.block {
+				t.Errorf("expected block of %s, got %s", test.block, block.Block.BlockIdentifier.Hash)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethRpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// NodeStatus gives access to the sync state and the peers of a CKB node, which the rich node
+// rpc.Client does not expose completely.
+type NodeStatus interface {
+	// SyncState returns the progress of the node syncing the chain.
+	SyncState(ctx context.Context) (*SyncState, error)
+
+	// GetPeers returns the peers connected to the node.
+	GetPeers(ctx context.Context) ([]*RemoteNode, error)
+}
+
+// SyncState is the progress of a node syncing the chain.
+type SyncState struct {
+	// Ibd is set while the node is in initial block download.
+	Ibd                  bool           `json:"ibd"`
+	BestKnownBlockNumber hexutil.Uint64 `json:"best_known_block_number"`
+}
+
+// RemoteNode is a peer connected to a node.
+type RemoteNode struct {
+	NodeId     string                `json:"node_id"`
+	Version    string                `json:"version"`
+	IsOutbound bool                  `json:"is_outbound"`
+	Addresses  []*RemoteNodeAddress  `json:"addresses"`
+	Protocols  []*RemoteNodeProtocol `json:"protocols"`
+}
+
+// RemoteNodeAddress is an address a peer is reachable at.
+type RemoteNodeAddress struct {
+	Address string `json:"address"`
+}
+
+// RemoteNodeProtocol is a protocol a peer is connected with.
+type RemoteNodeProtocol struct {
+	ID      hexutil.Uint64 `json:"id"`
+	Version string         `json:"version"`
+}
+
+type nodeStatus struct {
+	c *ethRpc.Client
+}
+
+// NewNodeStatus creates a NodeStatus calling the node rpc through c.
+func NewNodeStatus(c *ethRpc.Client) NodeStatus {
+	return &nodeStatus{c}
+}
+
+func (s *nodeStatus) SyncState(ctx context.Context) (*SyncState, error) {
+	var result SyncState
+
+	err := s.c.CallContext(ctx, &result, "sync_state")
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (s *nodeStatus) GetPeers(ctx context.Context) ([]*RemoteNode, error) {
+	var result []*RemoteNode
+
+	err := s.c.CallContext(ctx, &result, "get_peers")
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+// transactionMapper maps CKB transactions to Rosetta operations. It is shared by the block and
+// mempool services so that a transaction is described the same way before and after it is committed.
+type transactionMapper struct {
+	network *types.NetworkIdentifier
+	client  ChainClient
+	udts    *UdtRegistry
+}
+
+// processTransaction appends the input, output and fee operations of a non-cellbase transaction.
+// Cells created by the inputs must be present in inputTxCache.
+func (m *transactionMapper) processTransaction(
+	ctx context.Context,
+	tx *typesCKB.Transaction,
+	inputTxCache map[string]*typesCKB.TransactionWithStatus,
+	optIndex int64,
+	transaction *types.Transaction,
+) (int64, error) {
+	var inputsCapacity, outputsCapacity uint64
+	for _, input := range tx.Inputs {
+		inputTx := inputTxCache[input.PreviousOutput.TxHash.String()]
+		if inputTx == nil || int(input.PreviousOutput.Index) >= len(inputTx.Transaction.Outputs) {
+			return 0, fmt.Errorf("input cell %s:%d not found", input.PreviousOutput.TxHash.String(), input.PreviousOutput.Index)
+		}
+		output := inputTx.Transaction.Outputs[input.PreviousOutput.Index]
+		data := inputTx.Transaction.OutputsData[input.PreviousOutput.Index]
+
+		opType := "Transfer"
+		capacity := output.Capacity
+		var interest uint64
+		if isDaoScript(output.Type) {
+			if isDaoDeposit(data) {
+				opType = "DaoWithdrawPrepare"
+			} else {
+				// the prepared cell is spent for its capacity, the compensation it earned is
+				// minted into the transaction by a DaoInterest operation debiting DaoAccount
+				opType = "DaoWithdraw"
+				maximum, err := m.daoMaximumWithdraw(ctx, inputTx, input.PreviousOutput.Index)
+				if err != nil {
+					return 0, err
+				}
+				if maximum > capacity {
+					interest = maximum - capacity
+				}
+			}
+		}
+		inputsCapacity += capacity + interest
+
+		address := GenerateAddress(m.network, output.Lock)
+		transaction.Operations = append(transaction.Operations, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: optIndex,
+			},
+			Type:   opType,
+			Status: "Success",
+			Account: &types.AccountIdentifier{
+				Address: address,
+			},
+			Amount: &types.Amount{
+				Value:    fmt.Sprintf("-%d", capacity),
+				Currency: CkbCurrency,
+			},
+			Metadata: coinChange(input.PreviousOutput.TxHash, input.PreviousOutput.Index, rosetta.CoinSpent),
+		})
+		optIndex++
+
+		if interest > 0 {
+			transaction.Operations = append(transaction.Operations, &types.Operation{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: optIndex,
+				},
+				RelatedOperations: []*types.OperationIdentifier{
+					{
+						Index: optIndex - 1,
+					},
+				},
+				Type:    "DaoInterest",
+				Status:  "Success",
+				Account: DaoAccount,
+				Amount: &types.Amount{
+					Value:    fmt.Sprintf("-%d", interest),
+					Currency: CkbCurrency,
+				},
+			})
+			optIndex++
+		}
+
+		if currency, amount := m.udts.Amount(output.Type, data); currency != nil {
+			optIndex = appendUdtOperation(address, currency, amount.Neg(amount), optIndex, transaction)
+		}
+	}
+
+	for i, output := range tx.Outputs {
+		outputsCapacity += output.Capacity
+
+		opType := "Transfer"
+		if isDaoScript(output.Type) {
+			if isDaoDeposit(tx.OutputsData[i]) {
+				opType = "DaoDeposit"
+			} else {
+				opType = "DaoWithdrawPrepare"
+			}
+		}
+
+		address := GenerateAddress(m.network, output.Lock)
+		transaction.Operations = append(transaction.Operations, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: optIndex,
+			},
+			Type:   opType,
+			Status: "Success",
+			Account: &types.AccountIdentifier{
+				Address: address,
+			},
+			Amount: &types.Amount{
+				Value:    fmt.Sprintf("%d", output.Capacity),
+				Currency: CkbCurrency,
+			},
+			Metadata: coinChange(tx.Hash, uint(i), rosetta.CoinCreated),
+		})
+		optIndex++
+
+		if currency, amount := m.udts.Amount(output.Type, tx.OutputsData[i]); currency != nil {
+			optIndex = appendUdtOperation(address, currency, amount, optIndex, transaction)
+		}
+	}
+
+	return appendFeeOperation(inputsCapacity, outputsCapacity, optIndex, transaction), nil
+}
+
+// daoMaximumWithdraw returns the capacity that can be withdrawn from the prepared DAO cell at index
+// of prepareTx, including the compensation accrued since the deposit.
+func (m *transactionMapper) daoMaximumWithdraw(ctx context.Context, prepareTx *typesCKB.TransactionWithStatus, index uint) (uint64, error) {
+	if prepareTx.TxStatus == nil || prepareTx.TxStatus.BlockHash == nil || int(index) >= len(prepareTx.Transaction.Inputs) {
+		return 0, fmt.Errorf("invalid dao withdraw transaction %s", prepareTx.Transaction.Hash.String())
+	}
+
+	// the deposit cell and the prepared cell share the same index in the phase 1 transaction
+	return m.client.CalculateDaoMaximumWithdraw(
+		ctx,
+		prepareTx.Transaction.Inputs[index].PreviousOutput,
+		*prepareTx.TxStatus.BlockHash,
+	)
+}
+
+// processCellbase appends the reward operations of a cellbase. When the cellbase pays a single miner
+// the reward is split into its primary, secondary, proposal and commit components. The last of them
+// records the creation of the cell and relates to the others, the capacity of the cell being the
+// sum of the amounts of the operation and its related operations. The fee part of the reward is
+// paid out of FeeAccount.
+func (m *transactionMapper) processCellbase(ctx context.Context, blockHash typesCKB.Hash, tx *typesCKB.Transaction, optIndex int64, transaction *types.Transaction) (int64, error) {
+	outputs := tx.Outputs
+	reward, err := m.client.GetCellbaseOutputCapacityDetails(ctx, blockHash)
+	if err != nil {
+		return 0, err
+	}
+
+	if reward != nil && reward.Total != nil && len(outputs) == 1 && reward.Total.Cmp(new(big.Int).SetUint64(outputs[0].Capacity)) == 0 {
+		miner := &types.AccountIdentifier{
+			Address: GenerateAddress(m.network, outputs[0].Lock),
+		}
+		components := []struct {
+			opType string
+			amount *big.Int
+		}{
+			{"PrimaryReward", reward.Primary},
+			{"SecondaryReward", reward.Secondary},
+			{"ProposalReward", reward.ProposalReward},
+			{"CommitReward", reward.TxFee},
+		}
+		var parts []*types.Operation
+		for _, component := range components {
+			if component.amount == nil || component.amount.Sign() == 0 {
+				continue
+			}
+			parts = append(parts, &types.Operation{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: optIndex,
+				},
+				Type:    component.opType,
+				Status:  "Success",
+				Account: miner,
+				Amount: &types.Amount{
+					Value:    component.amount.String(),
+					Currency: CkbCurrency,
+				},
+			})
+			optIndex++
+		}
+		if len(parts) > 0 {
+			last := parts[len(parts)-1]
+			for _, part := range parts[:len(parts)-1] {
+				last.RelatedOperations = append(last.RelatedOperations, part.OperationIdentifier)
+			}
+			last.Metadata = coinChange(tx.Hash, 0, rosetta.CoinCreated)
+		}
+		transaction.Operations = append(transaction.Operations, parts...)
+	} else {
+		for i, output := range outputs {
+			transaction.Operations = append(transaction.Operations, &types.Operation{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: optIndex,
+				},
+				Type:   "Reward",
+				Status: "Success",
+				Account: &types.AccountIdentifier{
+					Address: GenerateAddress(m.network, output.Lock),
+				},
+				Amount: &types.Amount{
+					Value:    fmt.Sprintf("%d", output.Capacity),
+					Currency: CkbCurrency,
+				},
+				Metadata: coinChange(tx.Hash, uint(i), rosetta.CoinCreated),
+			})
+			optIndex++
+		}
+	}
+
+	// genesis cellbase has no reward details
+	if reward == nil {
+		return optIndex, nil
+	}
+	fee := new(big.Int)
+	if reward.ProposalReward != nil {
+		fee.Add(fee, reward.ProposalReward)
+	}
+	if reward.TxFee != nil {
+		fee.Add(fee, reward.TxFee)
+	}
+	if fee.Sign() == 0 {
+		return optIndex, nil
+	}
+
+	transaction.Operations = append(transaction.Operations, &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: optIndex,
+		},
+		Type:    "Fee",
+		Status:  "Success",
+		Account: FeeAccount,
+		Amount: &types.Amount{
+			Value:    new(big.Int).Neg(fee).String(),
+			Currency: CkbCurrency,
+		},
+	})
+	optIndex++
+
+	return optIndex, nil
+}
+
+// appendFeeOperation appends an operation crediting the difference between the inputs
+// and outputs capacity of a transaction to FeeAccount.
+func appendFeeOperation(inputsCapacity uint64, outputsCapacity uint64, optIndex int64, transaction *types.Transaction) int64 {
+	fee := new(big.Int).Sub(new(big.Int).SetUint64(inputsCapacity), new(big.Int).SetUint64(outputsCapacity))
+	if fee.Sign() == 0 {
+		return optIndex
+	}
+
+	transaction.Operations = append(transaction.Operations, &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: optIndex,
+		},
+		Type:    "Fee",
+		Status:  "Success",
+		Account: FeeAccount,
+		Amount: &types.Amount{
+			Value:    fee.String(),
+			Currency: CkbCurrency,
+		},
+	})
+	optIndex++
+
+	return optIndex
+}
+
+// appendUdtOperation appends an operation moving amount of a sUDT token, related to the capacity
+// operation of the same cell which precedes it.
+func appendUdtOperation(address string, currency *types.Currency, amount *big.Int, optIndex int64, transaction *types.Transaction) int64 {
+	transaction.Operations = append(transaction.Operations, &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			Index: optIndex,
+		},
+		RelatedOperations: []*types.OperationIdentifier{
+			{
+				Index: optIndex - 1,
+			},
+		},
+		Type:   "Transfer",
+		Status: "Success",
+		Account: &types.AccountIdentifier{
+			Address: address,
+		},
+		Amount: &types.Amount{
+			Value:    amount.String(),
+			Currency: currency,
+		},
+	})
+	optIndex++
+
+	return optIndex
+}
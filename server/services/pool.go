@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	ethRpc "github.com/ethereum/go-ethereum/rpc"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+// TxPool gives access to the transactions waiting in the transaction pool of a CKB node, which
+// are not exposed by the rich node rpc.Client.
+type TxPool interface {
+	// GetRawTxPool returns the hashes of the pending and proposed transactions in the pool.
+	GetRawTxPool(ctx context.Context) (*RawTxPool, error)
+}
+
+// RawTxPool holds the hashes of the transactions in the transaction pool.
+type RawTxPool struct {
+	Pending  []typesCKB.Hash `json:"pending"`
+	Proposed []typesCKB.Hash `json:"proposed"`
+}
+
+type txPool struct {
+	c *ethRpc.Client
+}
+
+// NewTxPool creates a TxPool calling the node rpc through c.
+func NewTxPool(c *ethRpc.Client) TxPool {
+	return &txPool{c}
+}
+
+func (p *txPool) GetRawTxPool(ctx context.Context) (*RawTxPool, error) {
+	var result RawTxPool
+
+	err := p.c.CallContext(ctx, &result, "get_raw_tx_pool", false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
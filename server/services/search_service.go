@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	"github.com/ququzone/ckb-rich-sdk-go/indexer"
+	"github.com/ququzone/ckb-sdk-go/address"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+const (
+	defaultSearchLimit = 25
+	maxSearchLimit     = 100
+)
+
+// SearchAPIService implements the rosetta.SearchAPIServicer interface.
+type SearchAPIService struct {
+	network *types.NetworkIdentifier
+	client  ChainClient
+	mapper  *transactionMapper
+}
+
+// NewSearchAPIService creates a new instance of a SearchAPIService.
+func NewSearchAPIService(network *types.NetworkIdentifier, client ChainClient, udts *UdtRegistry) rosetta.SearchAPIServicer {
+	return &SearchAPIService{
+		network: network,
+		client:  client,
+		mapper: &transactionMapper{
+			network: network,
+			client:  client,
+			udts:    udts,
+		},
+	}
+}
+
+// SearchTransactions implements the /search/transactions endpoint.
+func (s *SearchAPIService) SearchTransactions(
+	ctx context.Context,
+	request *rosetta.SearchTransactionsRequest,
+) (*rosetta.SearchTransactionsResponse, *rosetta.Error) {
+	target := request.Address
+	if request.AccountIdentifier != nil {
+		target = request.AccountIdentifier.Address
+	}
+	addr, err := address.Parse(target)
+	if err != nil {
+		return nil, wrapError(AddressError, err)
+	}
+
+	limit := int64(defaultSearchLimit)
+	if request.Limit != nil {
+		limit = *request.Limit
+	}
+	if limit <= 0 || limit > maxSearchLimit {
+		return nil, SearchError
+	}
+	if request.MinBlock != nil && request.MaxBlock != nil && *request.MinBlock > *request.MaxBlock {
+		return nil, SearchError
+	}
+
+	cursor, last, err := parseSearchCursor(request.Cursor)
+	if err != nil {
+		return nil, wrapError(SearchError, err)
+	}
+
+	// the indexer lists a transaction once per input and output of the address, so a page of
+	// limit records holds at most limit transactions
+	var records []*indexer.Transaction
+	seen := map[typesCKB.Hash]bool{last: true}
+	done := false
+	for !done && int64(len(records)) < limit {
+		requested := limit - int64(len(records))
+		txs, err := s.client.GetTransactions(ctx, &indexer.SearchKey{
+			Script:     addr.Script,
+			ScriptType: indexer.ScriptTypeLock,
+		}, indexer.SearchOrderDesc, uint64(requested), cursor)
+		if err != nil {
+			return nil, wrapError(RpcError, err)
+		}
+		cursor = txs.LastCursor
+		done = int64(len(txs.Objects)) < requested
+
+		for _, record := range txs.Objects {
+			if request.MaxBlock != nil && int64(record.BlockNumber) > *request.MaxBlock {
+				continue
+			}
+			if request.MinBlock != nil && int64(record.BlockNumber) < *request.MinBlock {
+				done = true
+				break
+			}
+			if seen[record.TxHash] {
+				continue
+			}
+			seen[record.TxHash] = true
+			records = append(records, record)
+		}
+	}
+
+	transactions, err := s.transactions(ctx, records)
+	if err != nil {
+		return nil, wrapError(RpcError, err)
+	}
+
+	response := &rosetta.SearchTransactionsResponse{
+		Transactions: transactions,
+	}
+	if !done && len(records) > 0 {
+		response.NextCursor = cursor + ":" + records[len(records)-1].TxHash.String()
+	}
+
+	return response, nil
+}
+
+// transactions maps the transactions of records, in the order of records.
+func (s *SearchAPIService) transactions(ctx context.Context, records []*indexer.Transaction) ([]*rosetta.BlockTransaction, error) {
+	result := []*rosetta.BlockTransaction{}
+	if len(records) == 0 {
+		return result, nil
+	}
+
+	hashes := make([]typesCKB.Hash, len(records))
+	for i, record := range records {
+		hashes[i] = record.TxHash
+	}
+	txCache, err := batchTransactions(ctx, s.client, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []*typesCKB.Transaction
+	for _, hash := range hashes {
+		if tx := txCache[hash.String()].Transaction; !isCellbase(tx) {
+			txs = append(txs, tx)
+		}
+	}
+	inputTxCache, err := fetchInputTransactions(ctx, s.client, txs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		tx := txCache[record.TxHash.String()]
+		if tx.TxStatus == nil || tx.TxStatus.BlockHash == nil {
+			return nil, fmt.Errorf("transaction %s is not committed", record.TxHash.String())
+		}
+
+		transaction := &types.Transaction{
+			TransactionIdentifier: &types.TransactionIdentifier{
+				Hash: tx.Transaction.Hash.String(),
+			},
+			Operations: []*types.Operation{},
+		}
+		if isCellbase(tx.Transaction) {
+			_, err = s.mapper.processCellbase(ctx, *tx.TxStatus.BlockHash, tx.Transaction, 0, transaction)
+		} else {
+			_, err = s.mapper.processTransaction(ctx, tx.Transaction, inputTxCache, 0, transaction)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &rosetta.BlockTransaction{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: int64(record.BlockNumber),
+				Hash:  tx.TxStatus.BlockHash.String(),
+			},
+			Transaction: transaction,
+		})
+	}
+
+	return result, nil
+}
+
+// parseSearchCursor splits a search cursor into the indexer cursor and the hash of the last
+// transaction returned, whose remaining records are skipped.
+func parseSearchCursor(cursor string) (string, typesCKB.Hash, error) {
+	if cursor == "" {
+		return "", typesCKB.Hash{}, nil
+	}
+	parts := strings.Split(cursor, ":")
+	if len(parts) != 2 || len(parts[1]) != 66 {
+		return "", typesCKB.Hash{}, errInvalidCursor
+	}
+
+	return parts[0], typesCKB.HexToHash(parts[1]), nil
+}
+
+func isCellbase(tx *typesCKB.Transaction) bool {
+	return len(tx.Inputs) > 0 && tx.Inputs[0].PreviousOutput.TxHash == typesCKB.Hash{}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+)
+
+func TestSearchTransactions(t *testing.T) {
+	one := int64(1)
+	minBlock := int64(4000000)
+	tests := []struct {
+		name    string
+		request *rosetta.SearchTransactionsRequest
+		golden  string
+		err     *rosetta.Error
+	}{
+		{
+			name: "block range",
+			request: &rosetta.SearchTransactionsRequest{
+				AccountIdentifier: &types.AccountIdentifier{Address: "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd"},
+				MinBlock:          &minBlock,
+			},
+			golden: "search_transactions_response",
+		},
+		{
+			name: "first page",
+			request: &rosetta.SearchTransactionsRequest{
+				Address: "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+				Limit:   &one,
+			},
+			golden: "search_transactions_page_response",
+		},
+		{
+			name: "next page",
+			request: &rosetta.SearchTransactionsRequest{
+				Address:  "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+				MinBlock: &minBlock,
+				Limit:    &one,
+				Cursor:   "0xc1:0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421",
+			},
+			golden: "search_transactions_last_page_response",
+		},
+		{
+			name: "invalid cursor",
+			request: &rosetta.SearchTransactionsRequest{
+				Address: "ckb1qyqrdsefa43s6m882pcj53m4gdnj4k440axqdt9rtd",
+				Cursor:  "0xc1",
+			},
+			err: SearchError,
+		},
+		{
+			name:    "invalid address",
+			request: &rosetta.SearchTransactionsRequest{Address: "ckb1invalid"},
+			err:     AddressError,
+		},
+	}
+
+	service := NewSearchAPIService(mainnet, newFixtureClient(t, "block", "search"), nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.request.NetworkIdentifier = mainnet
+			response, err := service.SearchTransactions(context.Background(), test.request)
+			assertError(t, test.err, err)
+			if test.golden != "" {
+				assertJSON(t, test.golden, response)
+			}
+		})
+	}
+}
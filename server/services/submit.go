@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ququzone/ckb-coinbase-sdk/server/rosetta"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+// shannonsPerByte is the capacity occupied by a byte of a cell, one CKByte.
+const shannonsPerByte = 100000000
+
+// The json-rpc error codes of a CKB node rejecting a transaction sent to its transaction pool.
+const (
+	ckbTransactionFailedToResolve                      = -301
+	ckbTransactionFailedToVerify                       = -302
+	ckbPoolRejectedTransactionByOutputsValidator       = -1102
+	ckbPoolRejectedTransactionByIllTransactionChecker  = -1103
+	ckbPoolRejectedTransactionByMinFeeRate             = -1104
+	ckbPoolRejectedTransactionByMaxAncestorsCountLimit = -1105
+	ckbPoolIsFull                                      = -1106
+	ckbPoolRejectedDuplicatedTransaction               = -1107
+	ckbPoolRejectedMalformedTransaction                = -1108
+)
+
+var submitErrors = map[int]*rosetta.Error{
+	ckbTransactionFailedToVerify:                       VerificationError,
+	ckbPoolRejectedTransactionByOutputsValidator:       TransactionRejectedError,
+	ckbPoolRejectedTransactionByIllTransactionChecker:  TransactionRejectedError,
+	ckbPoolRejectedTransactionByMinFeeRate:             FeeRateError,
+	ckbPoolRejectedTransactionByMaxAncestorsCountLimit: AncestorsLimitError,
+	ckbPoolIsFull:                        PoolFullError,
+	ckbPoolRejectedDuplicatedTransaction: DuplicateTransactionError,
+	ckbPoolRejectedMalformedTransaction:  TransactionRejectedError,
+}
+
+// submitError classifies the error returned by the node for a transaction sent to it. The details
+// hold the message and json-rpc error code of the node, errors which are not a rejection of the
+// transaction are reported as SubmitError.
+func submitError(err error) *rosetta.Error {
+	rpcErr, ok := err.(interface{ ErrorCode() int })
+	if !ok {
+		return wrapError(SubmitError, err)
+	}
+
+	result, ok := submitErrors[rpcErr.ErrorCode()]
+	if rpcErr.ErrorCode() == ckbTransactionFailedToResolve {
+		// an unknown cell may be created by a transaction the node has not received yet
+		result = UnknownCellError
+		if strings.Contains(err.Error(), "Dead(") {
+			result = DeadCellError
+		}
+	} else if !ok {
+		result = SubmitError
+	}
+
+	wrapped := wrapError(result, err)
+	wrapped.Details["code"] = rpcErr.ErrorCode()
+
+	return wrapped
+}
+
+// validateTransaction checks the structure of tx and that its cell deps are live cells of the
+// node, so that a transaction the node would reject is reported precisely before it is sent.
+func validateTransaction(ctx context.Context, cells CellStatus, tx *typesCKB.Transaction) *rosetta.Error {
+	if len(tx.Inputs) == 0 || len(tx.Outputs) == 0 {
+		return wrapError(TransactionError, errors.New("transaction has no inputs or no outputs"))
+	}
+	if len(tx.OutputsData) != len(tx.Outputs) {
+		return wrapError(OutputsDataError, fmt.Errorf("%d outputs data for %d outputs", len(tx.OutputsData), len(tx.Outputs)))
+	}
+	if len(tx.Witnesses) < len(tx.Inputs) {
+		return wrapError(WitnessError, fmt.Errorf("%d witnesses for %d inputs", len(tx.Witnesses), len(tx.Inputs)))
+	}
+	for i, output := range tx.Outputs {
+		occupied := occupiedCapacity(output, tx.OutputsData[i])
+		if output.Capacity < occupied {
+			return wrapError(CapacityError, fmt.Errorf("output %d has capacity %d, occupies %d", i, output.Capacity, occupied))
+		}
+	}
+
+	for i, dep := range tx.CellDeps {
+		status, err := cells.GetCellStatus(ctx, dep.OutPoint)
+		if err != nil {
+			return wrapError(RpcError, err)
+		}
+		switch status {
+		case "live":
+		case "dead":
+			return wrapError(DeadCellError, fmt.Errorf("cell dep %d %s is dead", i, coinIdentifier(dep.OutPoint.TxHash, dep.OutPoint.Index)))
+		default:
+			return wrapError(UnknownCellError, fmt.Errorf("cell dep %d %s is unknown", i, coinIdentifier(dep.OutPoint.TxHash, dep.OutPoint.Index)))
+		}
+	}
+
+	return nil
+}
+
+// occupiedCapacity returns the capacity, in shannons, occupied by output holding data.
+func occupiedCapacity(output *typesCKB.CellOutput, data []byte) uint64 {
+	bytes := 8 + scriptSize(output.Lock) + uint64(len(data))
+	if output.Type != nil {
+		bytes += scriptSize(output.Type)
+	}
+
+	return bytes * shannonsPerByte
+}
+
+func scriptSize(script *typesCKB.Script) uint64 {
+	return uint64(len(script.CodeHash.Bytes())) + 1 + uint64(len(script.Args))
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ququzone/ckb-sdk-go/types"
@@ -55,11 +57,112 @@ type inTransaction struct {
 	Witnesses   []hexutil.Bytes `json:"witnesses"`
 }
 
+// constructionTransaction is the transaction exchanged by the construction endpoints. It carries
+// the cells consumed by the inputs so that the transaction can be parsed without a node.
+type constructionTransaction struct {
+	Transaction *transaction `json:"transaction"`
+	InputCells  []cellOutput `json:"input_cells"`
+}
+
+// ToTransaction decodes a transaction in JSON form, either bare or wrapped by the construction
+// endpoints, or its molecule encoding in hex.
 func ToTransaction(data string) (*types.Transaction, error) {
-	var tx transaction
-	if err := json.Unmarshal([]byte(data), &tx); err != nil {
-		return nil, err
+	tx, _, err := toConstructionTransaction(data)
+	return tx, err
+}
+
+func toConstructionTransaction(data string) (*types.Transaction, []*types.CellOutput, error) {
+	if strings.HasPrefix(data, "0x") {
+		encoded, err := hexutil.Decode(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		tx, err := DeserializeTransaction(encoded)
+		return tx, nil, err
+	}
+
+	var wrapped constructionTransaction
+	if err := json.Unmarshal([]byte(data), &wrapped); err != nil {
+		return nil, nil, err
+	}
+	if wrapped.Transaction != nil {
+		if err := checkLocks("output", wrapped.Transaction.Outputs); err != nil {
+			return nil, nil, err
+		}
+		if err := checkLocks("input cell", wrapped.InputCells); err != nil {
+			return nil, nil, err
+		}
+		tx := toTransaction(*wrapped.Transaction)
+		if err := verifyHash(tx); err != nil {
+			return nil, nil, err
+		}
+		return tx, toOutputs(wrapped.InputCells), nil
+	}
+
+	var decoded transaction
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		return nil, nil, err
+	}
+	if err := checkLocks("output", decoded.Outputs); err != nil {
+		return nil, nil, err
+	}
+	tx := toTransaction(decoded)
+	if err := verifyHash(tx); err != nil {
+		return nil, nil, err
+	}
+	return tx, nil, nil
+}
+
+// verifyHash sets the hash of tx to its computed hash, failing with errHashMismatch when tx
+// declares another hash.
+func verifyHash(tx *types.Transaction) error {
+	hash, err := tx.ComputeHash()
+	if err != nil {
+		return err
 	}
+	if tx.Hash != (types.Hash{}) && tx.Hash != hash {
+		return fmt.Errorf("%w: declared %s, computed %s", errHashMismatch, tx.Hash.String(), hash.String())
+	}
+	tx.Hash = hash
+	return nil
+}
+
+// checkLocks returns an error naming the first of outputs without a lock script.
+func checkLocks(name string, outputs []cellOutput) error {
+	for i, output := range outputs {
+		if output.Lock == nil {
+			return fmt.Errorf("%s %d has no lock", name, i)
+		}
+	}
+	return nil
+}
+
+func fromConstructionTransaction(tx *types.Transaction, inputCells []*types.CellOutput) (string, error) {
+	hash, err := tx.ComputeHash()
+	if err != nil {
+		return "", err
+	}
+	result := constructionTransaction{
+		Transaction: &transaction{
+			Version:     hexutil.Uint(tx.Version),
+			Hash:        hash,
+			HeaderDeps:  tx.HeaderDeps,
+			CellDeps:    fromCellDeps(tx.CellDeps),
+			Inputs:      fromInputs(tx.Inputs),
+			Outputs:     fromOutputs(tx.Outputs),
+			OutputsData: fromBytesArray(tx.OutputsData),
+			Witnesses:   fromBytesArray(tx.Witnesses),
+		},
+		InputCells: fromOutputs(inputCells),
+	}
+	data, err := json.Marshal(&result)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func toTransaction(tx transaction) *types.Transaction {
 	return &types.Transaction{
 		Version:     uint(tx.Version),
 		Hash:        tx.Hash,
@@ -69,7 +172,7 @@ func ToTransaction(data string) (*types.Transaction, error) {
 		Outputs:     toOutputs(tx.Outputs),
 		OutputsData: toBytesArray(tx.OutputsData),
 		Witnesses:   toBytesArray(tx.Witnesses),
-	}, nil
+	}
 }
 
 func toBytesArray(bytes []hexutil.Bytes) [][]byte {
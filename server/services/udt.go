@@ -0,0 +1,74 @@
+package services
+
+import (
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ququzone/ckb-coinbase-sdk/server/config"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+// UdtRegistry maps the args of simple UDT type scripts to the currencies they are reported as.
+type UdtRegistry struct {
+	codeHash   typesCKB.Hash
+	currencies map[string]*types.Currency
+}
+
+// NewUdtRegistry creates a registry of the sUDT tokens configured for a network.
+func NewUdtRegistry(c *config.Network) (*UdtRegistry, error) {
+	registry := &UdtRegistry{
+		codeHash:   typesCKB.HexToHash(c.SudtCodeHash),
+		currencies: make(map[string]*types.Currency),
+	}
+	for _, udt := range c.Udts {
+		args, err := hexutil.Decode(udt.Args)
+		if err != nil {
+			return nil, err
+		}
+		registry.currencies[hexutil.Encode(args)] = &types.Currency{
+			Symbol:   udt.Symbol,
+			Decimals: udt.Decimals,
+			Metadata: map[string]interface{}{
+				"args": hexutil.Encode(args),
+			},
+		}
+	}
+
+	return registry, nil
+}
+
+// Currency returns the currency of a registered sUDT type script, or nil if the script is not one.
+func (r *UdtRegistry) Currency(script *typesCKB.Script) *types.Currency {
+	if r == nil || script == nil || script.CodeHash != r.codeHash || script.HashType != typesCKB.HashTypeType {
+		return nil
+	}
+	return r.currencies[hexutil.Encode(script.Args)]
+}
+
+// Amount returns the currency and amount of a cell of a registered sUDT with type script and data,
+// or a nil currency if the cell is not one or its data is too short to hold an amount, in which case
+// the cell only holds CKB.
+func (r *UdtRegistry) Amount(script *typesCKB.Script, data []byte) (*types.Currency, *big.Int) {
+	currency := r.Currency(script)
+	if currency == nil {
+		return nil, nil
+	}
+	amount, ok := udtAmount(data)
+	if !ok {
+		return nil, nil
+	}
+	return currency, amount
+}
+
+// udtAmount decodes the u128 little endian amount stored in the first 16 bytes of sUDT cell data.
+func udtAmount(data []byte) (*big.Int, bool) {
+	if len(data) < 16 {
+		return nil, false
+	}
+	amount := make([]byte, 16)
+	for i := 0; i < 16; i++ {
+		amount[i] = data[15-i]
+	}
+	return new(big.Int).SetBytes(amount), true
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ququzone/ckb-coinbase-sdk/server/config"
+	typesCKB "github.com/ququzone/ckb-sdk-go/types"
+)
+
+func newTestUdtRegistry(t *testing.T) *UdtRegistry {
+	registry, err := NewUdtRegistry(&config.Network{
+		SudtCodeHash: "0x5e7a36a77e68eecc013dfa2fe6a23f3b6c344b04005808694ae6dd45eea4cfd5",
+		Udts: []config.Udt{
+			{Symbol: "TST", Decimals: 8, Args: "0x36c329ed630d6ce750712a477543672adab57f4c"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create registry: %v", err)
+	}
+
+	return registry
+}
+
+func udtScript(args string) *typesCKB.Script {
+	return &typesCKB.Script{
+		CodeHash: typesCKB.HexToHash("0x5e7a36a77e68eecc013dfa2fe6a23f3b6c344b04005808694ae6dd45eea4cfd5"),
+		HashType: typesCKB.HashTypeType,
+		Args:     hexutil.MustDecode(args),
+	}
+}
+
+func TestUdtRegistryAmount(t *testing.T) {
+	registry := newTestUdtRegistry(t)
+
+	tests := []struct {
+		name   string
+		script *typesCKB.Script
+		data   string
+		amount string
+	}{
+		{
+			name:   "registered",
+			script: udtScript("0x36c329ed630d6ce750712a477543672adab57f4c"),
+			data:   "0x00e1f505000000000000000000000000",
+			amount: "100000000",
+		},
+		{
+			name:   "extra data",
+			script: udtScript("0x36c329ed630d6ce750712a477543672adab57f4c"),
+			data:   "0x00e1f505000000000000000000000000ff",
+			amount: "100000000",
+		},
+		{
+			name:   "data too short",
+			script: udtScript("0x36c329ed630d6ce750712a477543672adab57f4c"),
+			data:   "0x00e1f505",
+		},
+		{
+			name:   "not registered",
+			script: udtScript("0xe2fa82e70b062c8644b80ad7ecf6e015e5f352f6"),
+			data:   "0x00e1f505000000000000000000000000",
+		},
+		{
+			name: "no type",
+			data: "0x00e1f505000000000000000000000000",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			currency, amount := registry.Amount(test.script, hexutil.MustDecode(test.data))
+			if test.amount == "" {
+				if currency != nil {
+					t.Errorf("expected no currency, got %s %v", currency.Symbol, amount)
+				}
+				return
+			}
+			if currency == nil || currency.Symbol != "TST" || amount.String() != test.amount {
+				t.Errorf("expected %s TST, got %+v %v", test.amount, currency, amount)
+			}
+		})
+	}
+}
+
+func TestUdtOperations(t *testing.T) {
+	lock := secp256k1Lock(hexutil.MustDecode("0xc8328aabcd9b9e8e64fbc566c4385c3bdeb219d7"))
+	udt := udtScript("0x36c329ed630d6ce750712a477543672adab57f4c")
+	input := &typesCKB.Transaction{
+		Hash:        typesCKB.HexToHash("0xa332cfb34620c051cbef0cc82ac6fdbc0061b1681f091924b20134df75177f1d"),
+		Outputs:     []*typesCKB.CellOutput{{Capacity: 30000000000, Lock: lock, Type: udt}},
+		OutputsData: [][]byte{hexutil.MustDecode("0x00e1f505000000000000000000000000")},
+	}
+	// the second output is a registered sUDT cell without an amount, which only holds CKB
+	tx := &typesCKB.Transaction{
+		Hash: typesCKB.HexToHash("0xdbd9d029c6b1d30ad8b6f44fb6e092d669b47fee78f69159e3ce36b17c8fb421"),
+		Inputs: []*typesCKB.CellInput{
+			{PreviousOutput: &typesCKB.OutPoint{TxHash: input.Hash, Index: 0}},
+		},
+		Outputs: []*typesCKB.CellOutput{
+			{Capacity: 15000000000, Lock: lock, Type: udt},
+			{Capacity: 15000000000, Lock: lock, Type: udt},
+		},
+		OutputsData: [][]byte{
+			hexutil.MustDecode("0x00e1f505000000000000000000000000"),
+			hexutil.MustDecode("0x01"),
+		},
+	}
+
+	mapper := &transactionMapper{network: mainnet, udts: newTestUdtRegistry(t)}
+	transaction := &types.Transaction{}
+	_, err := mapper.processTransaction(context.Background(), tx, map[string]*typesCKB.TransactionWithStatus{
+		input.Hash.String(): {Transaction: input},
+	}, 0, transaction)
+	if err != nil {
+		t.Fatalf("process transaction: %v", err)
+	}
+
+	var udts []string
+	for _, operation := range transaction.Operations {
+		if operation.Amount.Currency.Symbol == "TST" {
+			udts = append(udts, operation.Amount.Value)
+		}
+	}
+	if len(udts) != 2 || udts[0] != "-100000000" || udts[1] != "100000000" {
+		t.Errorf("expected the sUDT operations of the input and the first output, got %v", udts)
+	}
+}